@@ -0,0 +1,92 @@
+package classify
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GeneratedHeaderLines — сколько первых строк файла проверяется
+// на маркеры сгенерированного кода.
+const GeneratedHeaderLines = 30
+
+// IsVendored сообщает, лежит ли файл внутри директории со сторонним кодом
+// (vendor/, node_modules/ и т.п.).
+func IsVendored(path string) bool {
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(path)), "/")
+	for _, part := range parts {
+		for _, dir := range Current.VendoredDirs {
+			if part == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsGeneratedPath определяет сгенерированный файл по его имени.
+func IsGeneratedPath(path string) bool {
+	name := filepath.Base(path)
+	for _, f := range Current.GeneratedFiles {
+		if name == f {
+			return true
+		}
+	}
+	for _, s := range Current.GeneratedSuffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+	for _, p := range Current.GeneratedPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGeneratedHeader проверяет строку заголовка файла на маркеры
+// вроде "Code generated ... DO NOT EDIT.".
+func IsGeneratedHeader(line string) bool {
+	for _, re := range Current.headers {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMinified — эвристика минифицированного файла по средней длине строки.
+func IsMinified(totalBytes, lines int) bool {
+	if lines == 0 || Current.MinifiedAvgLineLength <= 0 {
+		return false
+	}
+	return totalBytes/lines > Current.MinifiedAvgLineLength
+}
+
+// IsTestFile определяет тестовый файл по соглашениям для языка language
+// и общим соглашениям "*". path лучше передавать относительно корня проекта,
+// чтобы директории выше него не влияли на результат.
+func IsTestFile(p, language string) bool {
+	slashPath := filepath.ToSlash(p)
+	name := path.Base(slashPath)
+	dir := "/" + path.Dir(slashPath) + "/"
+
+	for _, key := range []string{"*", language} {
+		conv, ok := Current.Tests[key]
+		if !ok || (key == language && language == "") {
+			continue
+		}
+		for _, pattern := range conv.FilePatterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		for _, d := range conv.Dirs {
+			if strings.Contains(dir, "/"+strings.Trim(d, "/")+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
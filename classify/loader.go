@@ -0,0 +1,62 @@
+package classify
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+//go:embed config.json
+var configData []byte
+
+func init() {
+	var cfg Config
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		logging.Fatal("classify: cannot unmarshal config.json: %v", err)
+	}
+	if err := apply(cfg); err != nil {
+		logging.Fatal("classify: invalid config.json: %v", err)
+	}
+}
+
+// LoadConfig заменяет правила классификации значениями из JSON-файла
+// того же формата, что и встроенный config.json.
+func LoadConfig(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("classify: cannot read config file %q: %w", filePath, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("classify: invalid JSON in %q: %w", filePath, err)
+	}
+	if err := apply(cfg); err != nil {
+		return fmt.Errorf("classify: invalid config %q: %w", filePath, err)
+	}
+	logging.Info("classify: loaded config from %s", filePath)
+	return nil
+}
+
+func apply(cfg Config) error {
+	for _, h := range cfg.GeneratedHeaders {
+		re, err := regexp.Compile(h)
+		if err != nil {
+			return fmt.Errorf("generated header pattern %q: %w", h, err)
+		}
+		cfg.headers = append(cfg.headers, re)
+	}
+	for lang, conv := range cfg.Tests {
+		for _, p := range conv.FilePatterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("test file pattern %q for %s: %w", p, lang, err)
+			}
+		}
+	}
+	Current = cfg
+	return nil
+}
@@ -0,0 +1,83 @@
+package classify_test
+
+import (
+	"testing"
+
+	"github.com/rfxxfy/LintVision/classify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsVendored(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"repo/vendor/github.com/a/b/b.go", true},
+		{"repo/web/node_modules/react/index.js", true},
+		{"repo/third_party/zlib/zlib.h", true},
+		{"repo/internal/vendoring.go", false},
+		{"repo/vendor.go", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classify.IsVendored(tt.path), tt.path)
+	}
+}
+
+func TestIsGeneratedPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"api/service.pb.go", true},
+		{"pkg/apis/zz_generated.deepcopy.go", true},
+		{"static/app.min.js", true},
+		{"web/package-lock.json", true},
+		{"proto/service_pb2.py", true},
+		{"main.go", false},
+		{"static/app.js", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classify.IsGeneratedPath(tt.path), tt.path)
+	}
+}
+
+func TestIsGeneratedHeader(t *testing.T) {
+	t.Parallel()
+	assert.True(t, classify.IsGeneratedHeader("// Code generated by protoc-gen-go. DO NOT EDIT."))
+	assert.True(t, classify.IsGeneratedHeader("# @generated by tool"))
+	assert.True(t, classify.IsGeneratedHeader("// This file was automatically generated"))
+	assert.False(t, classify.IsGeneratedHeader("// Package foo generates reports."))
+	assert.False(t, classify.IsGeneratedHeader("// DO NOT EDIT the order of these constants."),
+		"a bare DO NOT EDIT is a note for humans, not a generator header")
+}
+
+func TestIsMinified(t *testing.T) {
+	t.Parallel()
+	assert.True(t, classify.IsMinified(10000, 2))
+	assert.False(t, classify.IsMinified(4000, 100))
+	assert.False(t, classify.IsMinified(0, 0))
+}
+
+func TestIsTestFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path string
+		lang string
+		want bool
+	}{
+		{"stats/compute_test.go", "Go", true},
+		{"stats/compute.go", "Go", false},
+		{"app/test_views.py", "Python", true},
+		{"app/views.py", "Python", false},
+		{"web/src/button.spec.ts", "TypeScript", true},
+		{"service/src/test/java/com/acme/Helper.java", "Java", true},
+		{"service/src/main/java/com/acme/Helper.java", "Java", false},
+		{"tests/fixtures.json", "", true},
+		{"contest/main.go", "Go", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classify.IsTestFile(tt.path, tt.lang), tt.path)
+	}
+}
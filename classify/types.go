@@ -0,0 +1,25 @@
+package classify
+
+import "regexp"
+
+type Config struct {
+	VendoredDirs          []string `json:"vendored_dirs"`
+	GeneratedSuffixes     []string `json:"generated_suffixes"`
+	GeneratedPrefixes     []string `json:"generated_prefixes"`
+	GeneratedFiles        []string `json:"generated_files"`
+	GeneratedHeaders      []string `json:"generated_headers"`
+	MinifiedAvgLineLength int      `json:"minified_avg_line_length"`
+
+	// Tests — соглашения об именовании тестов по названию языка;
+	// ключ "*" применяется ко всем языкам.
+	Tests map[string]TestConventions `json:"tests"`
+
+	headers []*regexp.Regexp
+}
+
+type TestConventions struct {
+	FilePatterns []string `json:"file_patterns"`
+	Dirs         []string `json:"dirs"`
+}
+
+var Current Config
@@ -0,0 +1,170 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// Locations — пути, где GitHub и GitLab ищут CODEOWNERS, относительно
+// корня репозитория. Обе платформы используют только один файл; здесь
+// берётся первый найденный в этом порядке.
+var Locations = []string{
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// Rule — строка CODEOWNERS: шаблон пути и его владельцы.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	// Section — секция GitLab ("" — правила до первой секции).
+	Section string
+	Line    int
+
+	re *regexp.Regexp
+}
+
+// Ruleset — разобранный файл CODEOWNERS.
+type Ruleset struct {
+	Rules []Rule
+}
+
+// sectionHeader — заголовок секции GitLab: "[Имя]", "^[Имя]" (необязательная
+// секция) и "[Имя][2]" (число одобрений), после которого могут идти
+// владельцы по умолчанию.
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?(.*)$`)
+
+// Parse разбирает CODEOWNERS. Строки с неподдерживаемыми шаблонами
+// пропускаются с предупреждением, как это делает GitHub.
+func Parse(data []byte) (*Ruleset, error) {
+	rs := &Ruleset{}
+	var section string
+	var defaults []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	n := 0
+	for scanner.Scan() {
+		n++
+		fields := splitFields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if m := sectionHeader.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			section = m[1]
+			defaults = splitFields(m[2])
+			continue
+		}
+		rule := Rule{Pattern: fields[0], Owners: fields[1:], Section: section, Line: n}
+		if len(rule.Owners) == 0 {
+			rule.Owners = defaults
+		}
+		re, err := compilePattern(rule.Pattern)
+		if err != nil {
+			logging.Warn("codeowners: line %d: %v", n, err)
+			continue
+		}
+		rule.re = re
+		rs.Rules = append(rs.Rules, rule)
+	}
+	return rs, scanner.Err()
+}
+
+// splitFields делит строку на поля по пробелам, учитывая экранирование
+// ("\ " внутри шаблона, "\#" в его начале) и отбрасывая комментарий
+// от "#" в начале поля до конца строки.
+func splitFields(line string) []string {
+	var fields []string
+	var cur strings.Builder
+	inField := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+			inField = true
+		case c == ' ' || c == '\t':
+			if inField {
+				fields = append(fields, cur.String())
+				cur.Reset()
+				inField = false
+			}
+		case c == '#' && !inField:
+			return fields
+		default:
+			cur.WriteByte(c)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
+
+// Owners возвращает владельцев файла rel (путь относительно корня
+// в slash-нотации). В каждой секции действует последнее совпавшее
+// правило; владельцы из разных секций GitLab объединяются. nil — у
+// файла нет владельцев (нет совпавших правил или правило без владельцев).
+func (rs *Ruleset) Owners(rel string) []string {
+	last := make(map[string]int)
+	var sections []string
+	for i, r := range rs.Rules {
+		if !r.re.MatchString(rel) {
+			continue
+		}
+		if _, ok := last[r.Section]; !ok {
+			sections = append(sections, r.Section)
+		}
+		last[r.Section] = i
+	}
+	var owners []string
+	seen := make(map[string]bool)
+	for _, s := range sections {
+		for _, o := range rs.Rules[last[s]].Owners {
+			if key := strings.ToLower(o); !seen[key] {
+				seen[key] = true
+				owners = append(owners, o)
+			}
+		}
+	}
+	return owners
+}
+
+// Find возвращает путь к CODEOWNERS под root относительно root
+// (в slash-нотации) или пустую строку, если файла нет.
+func Find(root string) string {
+	for _, loc := range Locations {
+		if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(loc))); err == nil && info.Mode().IsRegular() {
+			return loc
+		}
+	}
+	return ""
+}
+
+// Load находит и разбирает CODEOWNERS под root и возвращает правила
+// и путь к файлу (как у Find). Если файла нет, возвращается nil без ошибки.
+func Load(root string) (*Ruleset, string, error) {
+	loc := Find(root)
+	if loc == "" {
+		return nil, "", nil
+	}
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(loc)))
+	if err != nil {
+		return nil, "", fmt.Errorf("codeowners: cannot read %s: %w", loc, err)
+	}
+	rs, err := Parse(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("codeowners: invalid %s: %w", loc, err)
+	}
+	logging.Info("codeowners: loaded %d rules from %s", len(rs.Rules), loc)
+	return rs, loc, nil
+}
@@ -0,0 +1,68 @@
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// compilePattern переводит шаблон пути CODEOWNERS в регулярное
+// выражение для путей относительно корня в slash-нотации.
+//
+// Правила те же, что у GitHub и GitLab (подмножество .gitignore):
+//   - шаблон с "/" в начале или в середине привязан к корню, без "/" —
+//     совпадает на любой глубине;
+//   - "*" и "?" не пересекают "/", "**" — любое число директорий;
+//   - шаблон, совпавший с директорией, относится ко всем файлам в ней,
+//     а "/" в конце требует, чтобы это была директория;
+//   - "dir/*" относится только к файлам непосредственно в dir.
+//
+// Отрицания ("!") и классы символов ("[...]") GitHub не поддерживает;
+// такие шаблоны отклоняются.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("negated pattern %q is not supported", pattern)
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("character class in pattern %q is not supported", pattern)
+	}
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case p == "*" || strings.HasSuffix(p, "/*"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,104 @@
+package codeowners_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/codeowners"
+	"github.com/stretchr/testify/assert"
+)
+
+const githubOwners = `# Владельцы по умолчанию
+*       @org/core
+
+*.js    @org/frontend   # inline comment
+/build/ @org/infra
+docs/*  docs@example.com
+apps/   @octocat
+**/logs @org/ops
+/scripts/**/deploy.sh @org/infra @alice
+my\ file.txt @alice
+\#notes.md @bob
+/vendor/
+!negated @nobody
+`
+
+func TestOwners_GitHub(t *testing.T) {
+	t.Parallel()
+	rs, err := codeowners.Parse([]byte(githubOwners))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, rs.Rules, 10, "the negated pattern is skipped")
+
+	tests := []struct {
+		path   string
+		owners []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"web/app.js", []string{"@org/frontend"}},
+		{"build/out.bin", []string{"@org/infra"}},
+		{"build/js/app.js", []string{"@org/infra"}},
+		{"src/build/x.go", []string{"@org/core"}},
+		{"docs/intro.md", []string{"docs@example.com"}},
+		{"docs/guide/deep.md", []string{"@org/core"}},
+		{"apps/web/main.go", []string{"@octocat"}},
+		{"src/apps/cli/main.go", []string{"@octocat"}},
+		{"logs/today.txt", []string{"@org/ops"}},
+		{"deep/dir/logs/a.txt", []string{"@org/ops"}},
+		{"scripts/deploy.sh", []string{"@org/infra", "@alice"}},
+		{"scripts/prod/eu/deploy.sh", []string{"@org/infra", "@alice"}},
+		{"my file.txt", []string{"@alice"}},
+		{"#notes.md", []string{"@bob"}},
+		{"vendor/lib/a.go", nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.owners, rs.Owners(tt.path), tt.path)
+	}
+}
+
+const gitlabOwners = `* @default
+
+[Frontend] @frontend-team
+*.js
+/web/legacy/ @legacy
+
+^[Docs][2] @docs-team
+*.md
+README.md @alice @Docs-Team
+`
+
+func TestOwners_GitLabSections(t *testing.T) {
+	t.Parallel()
+	rs, err := codeowners.Parse([]byte(gitlabOwners))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"@default", "@frontend-team"}, rs.Owners("web/app.js"))
+	assert.Equal(t, []string{"@default", "@legacy"}, rs.Owners("web/legacy/old.js"))
+	assert.Equal(t, []string{"@default", "@docs-team"}, rs.Owners("docs/guide.md"))
+	assert.Equal(t, []string{"@default", "@alice", "@Docs-Team"}, rs.Owners("README.md"))
+	assert.Equal(t, []string{"@default"}, rs.Owners("main.go"))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	rs, loc, err := codeowners.Load(root)
+	assert.NoError(t, err)
+	assert.Nil(t, rs)
+	assert.Empty(t, loc)
+
+	for _, p := range []string{"CODEOWNERS", ".github/CODEOWNERS"} {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		assert.NoError(t, os.WriteFile(full, []byte("* @"+filepath.Base(filepath.Dir(full))+"\n"), 0o644))
+	}
+	rs, loc, err = codeowners.Load(root)
+	assert.NoError(t, err)
+	assert.Equal(t, ".github/CODEOWNERS", loc, ".github takes precedence over the root")
+	if assert.NotNil(t, rs) {
+		assert.Equal(t, []string{"@.github"}, rs.Owners("a.go"))
+	}
+}
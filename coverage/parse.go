@@ -0,0 +1,244 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// Detect определяет формат файла покрытия по содержимому.
+func Detect(data []byte) (string, bool) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo, true
+	case bytes.HasPrefix(trimmed, []byte("TN:")), bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLCOV, true
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		var root struct{ XMLName xml.Name }
+		if xml.Unmarshal(trimmed, &root) == nil && root.XMLName.Local == "coverage" {
+			return FormatCobertura, true
+		}
+	}
+	return "", false
+}
+
+// Parse разбирает файл покрытия в формате format.
+func Parse(format string, data []byte) ([]File, error) {
+	switch format {
+	case FormatGo:
+		return parseGo(data)
+	case FormatLCOV:
+		return parseLCOV(data)
+	case FormatCobertura:
+		return parseCobertura(data)
+	}
+	return nil, fmt.Errorf("coverage: unsupported format %q", format)
+}
+
+// LoadProfile читает файл покрытия, определяя формат по содержимому.
+func LoadProfile(filePath string) ([]File, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("coverage: cannot read profile %q: %w", filePath, err)
+	}
+	format, ok := Detect(data)
+	if !ok {
+		return nil, fmt.Errorf("coverage: unknown profile format in %q", filePath)
+	}
+	files, err := Parse(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("coverage: invalid %s profile %q: %w", format, filePath, err)
+	}
+	logging.Info("coverage: loaded %d files from %s (%s)", len(files), filePath, format)
+	return files, nil
+}
+
+// Collect загружает все файлы покрытия и объединяет данные по одному
+// и тому же файлу, складывая число выполнений строк.
+func Collect(paths []string) ([]File, error) {
+	merged := make(map[string]File)
+	var order []string
+	for _, p := range paths {
+		files, err := LoadProfile(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			m, ok := merged[f.Path]
+			if !ok {
+				m = File{Path: f.Path, Lines: make(map[int]int)}
+				order = append(order, f.Path)
+			}
+			for line, hits := range f.Lines {
+				m.Lines[line] += hits
+			}
+			merged[f.Path] = m
+		}
+	}
+	sort.Strings(order)
+	result := make([]File, 0, len(order))
+	for _, p := range order {
+		result = append(result, merged[p])
+	}
+	return result, nil
+}
+
+// fileSet собирает строки по файлам в порядке первого упоминания.
+type fileSet struct {
+	files []File
+	index map[string]int
+}
+
+func (s *fileSet) file(p string) File {
+	p = normalizePath(p)
+	if s.index == nil {
+		s.index = make(map[string]int)
+	}
+	i, ok := s.index[p]
+	if !ok {
+		i = len(s.files)
+		s.index[p] = i
+		s.files = append(s.files, File{Path: p, Lines: make(map[int]int)})
+	}
+	return s.files[i]
+}
+
+// parseGo разбирает профиль go test -coverprofile. Строки профиля
+// описывают блоки "файл:начало.колонка,конец.колонка операторов счётчик";
+// каждой строке блока присваивается наибольший счётчик среди
+// содержащих её блоков.
+func parseGo(data []byte) ([]File, error) {
+	var set fileSet
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[colon+1:])
+		if colon < 0 || len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: malformed block %q", n, line)
+		}
+		start, end, ok := parseGoRange(fields[0])
+		count, err := strconv.Atoi(fields[2])
+		if !ok || err != nil {
+			return nil, fmt.Errorf("line %d: malformed block %q", n, line)
+		}
+		f := set.file(line[:colon])
+		for l := start; l <= end; l++ {
+			if hits, seen := f.Lines[l]; !seen || count > hits {
+				f.Lines[l] = count
+			}
+		}
+	}
+	return set.files, scanner.Err()
+}
+
+// parseGoRange разбирает "12.3,15.2" в номера первой и последней строки.
+func parseGoRange(s string) (start, end int, ok bool) {
+	from, to, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+	startLine, _, _ := strings.Cut(from, ".")
+	endLine, _, _ := strings.Cut(to, ".")
+	var err1, err2 error
+	start, err1 = strconv.Atoi(startLine)
+	end, err2 = strconv.Atoi(endLine)
+	return start, end, err1 == nil && err2 == nil && start <= end
+}
+
+// parseLCOV разбирает трассировочный файл LCOV: SF начинает файл,
+// DA задаёт строку и число её выполнений.
+func parseLCOV(data []byte) ([]File, error) {
+	var set fileSet
+	var current *File
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			f := set.file(strings.TrimPrefix(line, "SF:"))
+			current = &f
+		case strings.HasPrefix(line, "DA:"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: DA outside of a file record", n)
+			}
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed %q", n, line)
+			}
+			number, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: malformed %q", n, line)
+			}
+			current.Lines[number] += hits
+		case line == "end_of_record":
+			current = nil
+		}
+	}
+	return set.files, scanner.Err()
+}
+
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura разбирает XML Cobertura. Относительные имена файлов
+// дополняются первым элементом sources, если он есть.
+func parseCobertura(data []byte) ([]File, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	source := ""
+	if len(report.Sources) > 0 {
+		source = normalizePath(strings.TrimSpace(report.Sources[0]))
+	}
+	var set fileSet
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			name := normalizePath(class.Filename)
+			if source != "" && source != "." && !path.IsAbs(name) {
+				name = path.Join(source, name)
+			}
+			f := set.file(name)
+			for _, l := range class.Lines {
+				f.Lines[l.Number] += l.Hits
+			}
+		}
+	}
+	return set.files, nil
+}
+
+func normalizePath(p string) string {
+	p = strings.ReplaceAll(strings.TrimPrefix(p, "file://"), "\\", "/")
+	if p == "" {
+		return ""
+	}
+	return path.Clean(p)
+}
@@ -0,0 +1,118 @@
+package coverage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/coverage"
+	"github.com/stretchr/testify/assert"
+)
+
+const goProfile = `mode: set
+github.com/user/app/pkg/a.go:3.20,5.2 2 1
+github.com/user/app/pkg/a.go:5.2,7.3 1 0
+github.com/user/app/pkg/b.go:1.1,1.10 1 0
+`
+
+const lcovProfile = `TN:
+SF:/ci/work/src/app.js
+DA:1,4
+DA:2,0
+DA:5,1
+end_of_record
+SF:src/util.js
+DA:3,0
+end_of_record
+`
+
+const coberturaProfile = `<?xml version="1.0" ?>
+<coverage line-rate="0.5" version="7.3">
+  <sources><source>/ci/work</source></sources>
+  <packages>
+    <package name="app">
+      <classes>
+        <class name="main.py" filename="app/main.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="0"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	for data, want := range map[string]string{
+		goProfile:        coverage.FormatGo,
+		lcovProfile:      coverage.FormatLCOV,
+		"SF:a.js\n":      coverage.FormatLCOV,
+		coberturaProfile: coverage.FormatCobertura,
+	} {
+		got, ok := coverage.Detect([]byte(data))
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	for _, data := range []string{"", "{}", "<checkstyle/>", "hello"} {
+		_, ok := coverage.Detect([]byte(data))
+		assert.False(t, ok, data)
+	}
+}
+
+func TestParse_Go(t *testing.T) {
+	t.Parallel()
+	files, err := coverage.Parse(coverage.FormatGo, []byte(goProfile))
+	assert.NoError(t, err)
+	assert.Equal(t, []coverage.File{
+		// Строка 5 входит в оба блока и считается покрытой.
+		{Path: "github.com/user/app/pkg/a.go", Lines: map[int]int{3: 1, 4: 1, 5: 1, 6: 0, 7: 0}},
+		{Path: "github.com/user/app/pkg/b.go", Lines: map[int]int{1: 0}},
+	}, files)
+
+	covered, uncovered := files[0].Counts()
+	assert.Equal(t, 3, covered)
+	assert.Equal(t, 2, uncovered)
+
+	_, err = coverage.Parse(coverage.FormatGo, []byte("mode: set\na.go:1.1 1\n"))
+	assert.Error(t, err)
+}
+
+func TestParse_LCOV(t *testing.T) {
+	t.Parallel()
+	files, err := coverage.Parse(coverage.FormatLCOV, []byte(lcovProfile))
+	assert.NoError(t, err)
+	assert.Equal(t, []coverage.File{
+		{Path: "/ci/work/src/app.js", Lines: map[int]int{1: 4, 2: 0, 5: 1}},
+		{Path: "src/util.js", Lines: map[int]int{3: 0}},
+	}, files)
+
+	_, err = coverage.Parse(coverage.FormatLCOV, []byte("DA:1,1\n"))
+	assert.Error(t, err)
+}
+
+func TestParse_Cobertura(t *testing.T) {
+	t.Parallel()
+	files, err := coverage.Parse(coverage.FormatCobertura, []byte(coberturaProfile))
+	assert.NoError(t, err)
+	assert.Equal(t, []coverage.File{
+		{Path: "/ci/work/app/main.py", Lines: map[int]int{1: 1, 2: 0}},
+	}, files)
+}
+
+func TestCollect_MergesRuns(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	unit := filepath.Join(dir, "unit.info")
+	integration := filepath.Join(dir, "integration.info")
+	assert.NoError(t, os.WriteFile(unit, []byte("SF:a.js\nDA:1,1\nDA:2,0\nend_of_record\n"), 0o644))
+	assert.NoError(t, os.WriteFile(integration, []byte("SF:a.js\nDA:2,3\nend_of_record\n"), 0o644))
+
+	files, err := coverage.Collect([]string{unit, integration})
+	assert.NoError(t, err)
+	assert.Equal(t, []coverage.File{{Path: "a.js", Lines: map[int]int{1: 1, 2: 3}}}, files)
+
+	_, err = coverage.Collect([]string{filepath.Join(dir, "missing.out")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+package coverage
+
+// Форматы файлов покрытия.
+const (
+	FormatGo        = "go"
+	FormatLCOV      = "lcov"
+	FormatCobertura = "cobertura"
+)
+
+// File — покрытие одного файла: число выполнений по номерам строк.
+// Строка с нулём выполнений не покрыта; строк, которых нет в Lines,
+// инструмент покрытия не учитывал (пустые, комментарии, объявления).
+type File struct {
+	Path  string
+	Lines map[int]int
+}
+
+// Counts возвращает число покрытых и непокрытых строк.
+func (f File) Counts() (covered, uncovered int) {
+	for _, hits := range f.Lines {
+		if hits > 0 {
+			covered++
+		} else {
+			uncovered++
+		}
+	}
+	return covered, uncovered
+}
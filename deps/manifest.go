@@ -0,0 +1,169 @@
+package deps
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// IsManifest сообщает, умеет ли пакет разбирать файл с таким именем.
+func IsManifest(path string) bool {
+	switch filepath.Base(path) {
+	case "go.mod", "package.json", "requirements.txt":
+		return true
+	}
+	return false
+}
+
+func ParseManifest(path string) ([]Dependency, error) {
+	switch filepath.Base(path) {
+	case "go.mod":
+		return parseGoMod(path)
+	case "package.json":
+		return parsePackageJSON(path)
+	case "requirements.txt":
+		return parseRequirements(path)
+	}
+	return nil, fmt.Errorf("deps: unsupported manifest %s", path)
+}
+
+// Collect разбирает все манифесты среди paths и возвращает
+// отсортированный список зависимостей. Ошибки разбора логируются,
+// но не прерывают сбор.
+func Collect(paths []string) []Dependency {
+	var result []Dependency
+	for _, p := range paths {
+		if !IsManifest(p) {
+			continue
+		}
+		list, err := ParseManifest(p)
+		if err != nil {
+			logging.Warn("deps: cannot parse %s: %v", p, err)
+			continue
+		}
+		result = append(result, list...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Ecosystem != result[j].Ecosystem {
+			return result[i].Ecosystem < result[j].Ecosystem
+		}
+		return result[i].Name < result[j].Name
+	})
+	logging.Info("deps: found %d dependencies", len(result))
+	return result
+}
+
+func parseGoMod(path string) ([]Dependency, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []Dependency
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		indirect := strings.HasSuffix(line, "// indirect")
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		case !inBlock:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		result = append(result, Dependency{
+			Name:      fields[0],
+			Version:   fields[1],
+			Ecosystem: EcosystemGo,
+			Manifest:  path,
+			Indirect:  indirect,
+		})
+	}
+	return result, scanner.Err()
+}
+
+func parsePackageJSON(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	var result []Dependency
+	add := func(m map[string]string, dev bool) {
+		for name, version := range m {
+			result = append(result, Dependency{
+				Name:      name,
+				Version:   strings.TrimLeft(version, "^~=v "),
+				Ecosystem: EcosystemNPM,
+				Manifest:  path,
+				Dev:       dev,
+			})
+		}
+	}
+	add(pkg.Dependencies, false)
+	add(pkg.DevDependencies, true)
+	return result, nil
+}
+
+func parseRequirements(path string) ([]Dependency, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []Dependency
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if idx := strings.IndexAny(line, ";["); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		dep := Dependency{Name: line, Ecosystem: EcosystemPyPI, Manifest: path}
+		if idx := strings.Index(line, "=="); idx >= 0 {
+			dep.Name = strings.TrimSpace(line[:idx])
+			dep.Version = strings.TrimSpace(line[idx+2:])
+		} else if idx := strings.IndexAny(line, "<>=!~"); idx >= 0 {
+			dep.Name = strings.TrimSpace(line[:idx])
+		}
+		result = append(result, dep)
+	}
+	return result, scanner.Err()
+}
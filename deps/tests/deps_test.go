@@ -0,0 +1,99 @@
+package deps_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/deps"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	return path
+}
+
+func TestParseManifest_GoMod(t *testing.T) {
+	t.Parallel()
+	path := writeFile(t, t.TempDir(), "go.mod", `module example.com/app
+
+go 1.24
+
+require github.com/a/b v1.0.0
+
+require (
+	github.com/c/d v0.2.0
+	golang.org/x/sys v0.30.0 // indirect
+)
+`)
+	got, err := deps.ParseManifest(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []deps.Dependency{
+		{Name: "github.com/a/b", Version: "v1.0.0", Ecosystem: deps.EcosystemGo, Manifest: path},
+		{Name: "github.com/c/d", Version: "v0.2.0", Ecosystem: deps.EcosystemGo, Manifest: path},
+		{Name: "golang.org/x/sys", Version: "v0.30.0", Ecosystem: deps.EcosystemGo, Manifest: path, Indirect: true},
+	}, got)
+}
+
+func TestParseManifest_PackageJSON(t *testing.T) {
+	t.Parallel()
+	path := writeFile(t, t.TempDir(), "package.json",
+		`{"dependencies": {"@scope/lib": "^1.2.3"}, "devDependencies": {"jest": "~29.0.0"}}`)
+	got, err := deps.ParseManifest(path)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []deps.Dependency{
+		{Name: "@scope/lib", Version: "1.2.3", Ecosystem: deps.EcosystemNPM, Manifest: path},
+		{Name: "jest", Version: "29.0.0", Ecosystem: deps.EcosystemNPM, Manifest: path, Dev: true},
+	}, got)
+}
+
+func TestParseManifest_Requirements(t *testing.T) {
+	t.Parallel()
+	path := writeFile(t, t.TempDir(), "requirements.txt", `# comment
+requests==2.31.0
+-r other.txt
+flask>=2.0  # web
+uvicorn[standard]; python_version > "3.8"
+`)
+	got, err := deps.ParseManifest(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []deps.Dependency{
+		{Name: "requests", Version: "2.31.0", Ecosystem: deps.EcosystemPyPI, Manifest: path},
+		{Name: "flask", Ecosystem: deps.EcosystemPyPI, Manifest: path},
+		{Name: "uvicorn", Ecosystem: deps.EcosystemPyPI, Manifest: path},
+	}, got)
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	paths := []string{
+		writeFile(t, dir, "requirements.txt", "requests==2.31.0\n"),
+		writeFile(t, dir, "go.mod", "module x\n\nrequire github.com/a/b v1.0.0\n"),
+		writeFile(t, dir, "main.go", "package main\n"),
+	}
+	got := deps.Collect(paths)
+	assert.Len(t, got, 2)
+	assert.Equal(t, deps.EcosystemGo, got[0].Ecosystem)
+	assert.Equal(t, deps.EcosystemPyPI, got[1].Ecosystem)
+}
+
+func TestDependencyPURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		dep  deps.Dependency
+		want string
+	}{
+		{deps.Dependency{Name: "github.com/a/b", Version: "v1.0.0", Ecosystem: deps.EcosystemGo}, "pkg:golang/github.com/a/b@v1.0.0"},
+		{deps.Dependency{Name: "@scope/lib", Version: "1.2.3", Ecosystem: deps.EcosystemNPM}, "pkg:npm/%40scope/lib@1.2.3"},
+		{deps.Dependency{Name: "Foo_Bar", Ecosystem: deps.EcosystemPyPI}, "pkg:pypi/foo-bar"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.dep.PURL())
+	}
+}
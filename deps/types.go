@@ -0,0 +1,39 @@
+package deps
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	EcosystemGo   = "golang"
+	EcosystemNPM  = "npm"
+	EcosystemPyPI = "pypi"
+)
+
+type Dependency struct {
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	Ecosystem string `json:"ecosystem"`
+	Manifest  string `json:"manifest"`
+	Indirect  bool   `json:"indirect,omitempty"`
+	Dev       bool   `json:"dev,omitempty"`
+}
+
+// PURL возвращает package URL зависимости (https://github.com/package-url/purl-spec).
+func (d Dependency) PURL() string {
+	name := d.Name
+	switch d.Ecosystem {
+	case EcosystemNPM:
+		if strings.HasPrefix(name, "@") {
+			name = "%40" + name[1:]
+		}
+	case EcosystemPyPI:
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+	purl := "pkg:" + d.Ecosystem + "/" + name
+	if d.Version != "" {
+		purl += "@" + url.PathEscape(d.Version)
+	}
+	return purl
+}
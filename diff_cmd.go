@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/stats"
+)
+
+// runDiff выполняет `lintvision diff old.json new.json` и возвращает код выхода.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := fs.String("format", stats.FormatText, "формат вывода: "+strings.Join(stats.DiffFormats(), ", "))
+	out := fs.String("out", "", "файл для сохранения результата (по умолчанию stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Использование: lintvision diff [флаги] old.json new.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	oldStats, err := stats.LoadStats(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot load %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	newStats, err := stats.LoadStats(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot load %s: %v\n", fs.Arg(1), err)
+		return 1
+	}
+	d := stats.DiffStats(oldStats, newStats)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create %s: %v\n", *out, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := stats.WriteDiff(w, d, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write diff: %v\n", err)
+		return 1
+	}
+	return 0
+}
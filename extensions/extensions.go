@@ -17,6 +17,12 @@ func GetLanguageConfig(ext string) (languages.LanguageConfig, bool) {
 	return cfg, ok
 }
 
+// GetLanguageName возвращает название языка по расширению
+// или пустую строку, если язык неизвестен.
+func GetLanguageName(ext string) string {
+	return languages.Configs[ext].Name
+}
+
 func GetFileCategory(ext string) string {
 	if IsCodeExtension(ext) {
 		return "code"
@@ -27,22 +33,38 @@ func GetFileCategory(ext string) string {
 	return "unknown"
 }
 
+// IsCommentAfterCode сообщает, есть ли в строке однострочный комментарий
+// после кода.
 func IsCommentAfterCode(line, ext string) bool {
+	return CommentIndex(line, ext) > 0
+}
+
+// CommentIndex возвращает позицию токена однострочного комментария в line
+// или -1, если комментария нет или язык неизвестен. Токены внутри
+// строковых литералов (кавычки языка из его конфига, с экранированием
+// через \) пропускаются.
+func CommentIndex(line, ext string) int {
 	cfg, ok := GetLanguageConfig(ext)
-	if !ok {
-		return false
+	if !ok || cfg.SingleLineCommentToken == "" {
+		return -1
 	}
 	token := cfg.SingleLineCommentToken
-	idx := strings.Index(line, token)
-	if idx <= 0 {
-		return false
-	}
-	count := 0
-	for i := 0; i < idx; i++ {
+	quotes := cfg.DoubleQuote + cfg.SingleQuote
+	var quote byte
+	for i := 0; i < len(line); i++ {
 		ch := line[i]
-		if ch == '"' || ch == '\'' {
-			count++
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case strings.IndexByte(quotes, ch) >= 0:
+			quote = ch
+		case strings.HasPrefix(line[i:], token):
+			return i
 		}
 	}
-	return count%2 == 0
+	return -1
 }
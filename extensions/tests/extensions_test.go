@@ -52,6 +52,7 @@ func TestGetLanguageConfig(t *testing.T) {
 			assert.Equal(t, tt.wantOk, ok, "GetLanguageConfig(%q) ok", tt.ext)
 			if ok {
 				assert.Equal(t, tt.wantComm, cfg.SingleLineCommentToken, "GetLanguageConfig(%q) SingleLineCommentToken", tt.ext)
+				assert.Equal(t, tt.wantName, cfg.Name, "GetLanguageConfig(%q) Name", tt.ext)
 			}
 			// TODO: logging here
 		})
@@ -128,8 +129,18 @@ func TestIsCommentAfterCode(t *testing.T) {
 			want: false,
 		},
 		{
-			name: "Go: comment after code with odd quotes",
+			name: "Go: comment after code with escaped quotes",
 			args: args{line: `fmt.Println("\"hi\" // not comment") // comment`, ext: ".go"},
+			want: true,
+		},
+		{
+			name: "Go: comment token only inside string",
+			args: args{line: `s := "// TODO"`, ext: ".go"},
+			want: false,
+		},
+		{
+			name: "Py: comment token inside single quotes",
+			args: args{line: `s = '# not comment'`, ext: ".py"},
 			want: false,
 		},
 		{
@@ -147,4 +158,13 @@ func TestIsCommentAfterCode(t *testing.T) {
 			// TODO: logging here
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestCommentIndex(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 15, extensions.CommentIndex(`s := "// TODO" // real`, ".go"))
+	assert.Equal(t, 10, extensions.CommentIndex(`c := '\'' // quote`, ".go"))
+	assert.Equal(t, 0, extensions.CommentIndex(`# comment`, ".py"))
+	assert.Equal(t, -1, extensions.CommentIndex(`s := "// TODO"`, ".go"))
+	assert.Equal(t, -1, extensions.CommentIndex(`// comment`, ".txt"))
+}
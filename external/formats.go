@@ -0,0 +1,177 @@
+package external
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// checkstyle — XML-формат Checkstyle; его же умеют писать golangci-lint,
+// ESLint и многие другие линтеры.
+type checkstyleReport struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     int    `xml:"line,attr"`
+			Column   int    `xml:"column,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+func parseCheckstyle(data []byte) ([]Finding, error) {
+	var report checkstyleReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var result []Finding
+	for _, f := range report.Files {
+		for _, e := range f.Errors {
+			result = append(result, Finding{
+				Tool: FormatCheckstyle, Rule: e.Source, Severity: normalizeSeverity(e.Severity),
+				Path: NormalizePath(f.Name), Line: e.Line, Column: e.Column, Message: e.Message,
+			})
+		}
+	}
+	return result, nil
+}
+
+type sarifReport struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Name string `json:"name"`
+			} `json:"driver"`
+		} `json:"tool"`
+		OriginalURIBaseIDs map[string]struct {
+			URI string `json:"uri"`
+		} `json:"originalUriBaseIds"`
+		Results []struct {
+			RuleID string `json:"ruleId"`
+			Rule   struct {
+				ID string `json:"id"`
+			} `json:"rule"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI       string `json:"uri"`
+						URIBaseID string `json:"uriBaseId"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// parseSARIF берёт из каждого результата первое физическое
+// местоположение; результаты без него относятся ко всему проекту
+// и получают пустой путь.
+func parseSARIF(data []byte) ([]Finding, error) {
+	var report sarifReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var result []Finding
+	for _, run := range report.Runs {
+		tool := run.Tool.Driver.Name
+		for _, r := range run.Results {
+			f := Finding{Tool: tool, Rule: r.RuleID, Severity: normalizeSeverity(r.Level), Message: r.Message.Text}
+			if f.Rule == "" {
+				f.Rule = r.Rule.ID
+			}
+			if len(r.Locations) > 0 {
+				loc := r.Locations[0].PhysicalLocation
+				uri := loc.ArtifactLocation.URI
+				if base, ok := run.OriginalURIBaseIDs[loc.ArtifactLocation.URIBaseID]; ok && base.URI != "" {
+					uri = strings.TrimSuffix(base.URI, "/") + "/" + uri
+				}
+				f.Path = NormalizePath(uri)
+				f.Line = loc.Region.StartLine
+				f.Column = loc.Region.StartColumn
+			}
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+type eslintFile struct {
+	FilePath string `json:"filePath"`
+	Messages []struct {
+		RuleID   *string `json:"ruleId"`
+		Severity int     `json:"severity"`
+		Message  string  `json:"message"`
+		Line     int     `json:"line"`
+		Column   int     `json:"column"`
+		Fatal    bool    `json:"fatal"`
+	} `json:"messages"`
+}
+
+// parseESLint разбирает вывод ESLint -f json. Сообщения без правила
+// (ошибки разбора) получают правило "parse-error".
+func parseESLint(data []byte) ([]Finding, error) {
+	var files []eslintFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, err
+	}
+	var result []Finding
+	for _, file := range files {
+		for _, m := range file.Messages {
+			f := Finding{
+				Tool: FormatESLint, Severity: SeverityWarning,
+				Path: NormalizePath(file.FilePath), Line: m.Line, Column: m.Column, Message: m.Message,
+			}
+			if m.RuleID != nil {
+				f.Rule = *m.RuleID
+			} else if m.Fatal {
+				f.Rule = "parse-error"
+			}
+			if m.Severity == 2 || m.Fatal {
+				f.Severity = SeverityError
+			}
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+type golangciReport struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Severity   string `json:"Severity"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+			Column   int    `json:"Column"`
+		} `json:"Pos"`
+	} `json:"Issues"`
+}
+
+// parseGolangCI разбирает вывод golangci-lint --out-format json;
+// правилом считается имя линтера.
+func parseGolangCI(data []byte) ([]Finding, error) {
+	var report golangciReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var result []Finding
+	for _, issue := range report.Issues {
+		result = append(result, Finding{
+			Tool: FormatGolangCI, Rule: issue.FromLinter, Severity: normalizeSeverity(issue.Severity),
+			Path: NormalizePath(issue.Pos.Filename), Line: issue.Pos.Line, Column: issue.Pos.Column,
+			Message: issue.Text,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// Detect определяет формат отчёта по содержимому.
+func Detect(data []byte) (string, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return "", false
+	}
+	switch trimmed[0] {
+	case '<':
+		var root struct{ XMLName xml.Name }
+		if xml.Unmarshal(trimmed, &root) == nil && root.XMLName.Local == "checkstyle" {
+			return FormatCheckstyle, true
+		}
+	case '[':
+		var files []map[string]json.RawMessage
+		if json.Unmarshal(trimmed, &files) == nil && (len(files) == 0 || files[0]["filePath"] != nil) {
+			return FormatESLint, true
+		}
+	case '{':
+		var doc map[string]json.RawMessage
+		if json.Unmarshal(trimmed, &doc) != nil {
+			return "", false
+		}
+		if doc["runs"] != nil && doc["version"] != nil {
+			return FormatSARIF, true
+		}
+		if _, ok := doc["Issues"]; ok {
+			return FormatGolangCI, true
+		}
+	}
+	return "", false
+}
+
+// Parse разбирает отчёт в формате format.
+func Parse(format string, data []byte) ([]Finding, error) {
+	switch format {
+	case FormatCheckstyle:
+		return parseCheckstyle(data)
+	case FormatSARIF:
+		return parseSARIF(data)
+	case FormatESLint:
+		return parseESLint(data)
+	case FormatGolangCI:
+		return parseGolangCI(data)
+	}
+	return nil, fmt.Errorf("external: unsupported format %q", format)
+}
+
+// LoadReport читает отчёт из файла, определяя формат по содержимому.
+func LoadReport(filePath string) ([]Finding, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("external: cannot read report %q: %w", filePath, err)
+	}
+	format, ok := Detect(data)
+	if !ok {
+		return nil, fmt.Errorf("external: unknown report format in %q", filePath)
+	}
+	findings, err := Parse(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("external: invalid %s report %q: %w", format, filePath, err)
+	}
+	logging.Info("external: loaded %d findings from %s (%s)", len(findings), filePath, format)
+	return findings, nil
+}
+
+// Collect загружает все отчёты из paths. В отличие от deps.Collect,
+// ошибка в любом отчёте прерывает загрузку: отчёты указываются явно.
+func Collect(paths []string) ([]Finding, error) {
+	var result []Finding
+	for _, p := range paths {
+		findings, err := LoadReport(p)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, findings...)
+	}
+	return result, nil
+}
+
+// NormalizePath приводит путь из отчёта к виду со слешами: снимает
+// схему file://, декодирует URI и убирает "./".
+func NormalizePath(p string) string {
+	if strings.HasPrefix(p, "file:") {
+		if u, err := url.Parse(p); err == nil {
+			p = u.Path
+			// file:///C:/src/a.go
+			if len(p) > 2 && p[0] == '/' && p[2] == ':' {
+				p = p[1:]
+			}
+		}
+	} else if strings.Contains(p, "%") {
+		if decoded, err := url.PathUnescape(p); err == nil {
+			p = decoded
+		}
+	}
+	p = strings.ReplaceAll(p, "\\", "/")
+	if p == "" {
+		return ""
+	}
+	return path.Clean(p)
+}
+
+func normalizeSeverity(s string) string {
+	switch strings.ToLower(s) {
+	case "error", "fatal", "critical", "high", "blocker":
+		return SeverityError
+	case "info", "note", "none", "ignore", "low", "minor", "convention", "refactor":
+		return SeverityInfo
+	}
+	return SeverityWarning
+}
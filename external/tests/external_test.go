@@ -0,0 +1,142 @@
+package external_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/external"
+	"github.com/stretchr/testify/assert"
+)
+
+const checkstyleReport = `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="pkg/a.go">
+    <error line="3" column="2" severity="error" message="Error return value is not checked" source="errcheck"/>
+    <error line="7" severity="info" message="comment on exported function" source="golint"/>
+  </file>
+</checkstyle>`
+
+const sarifReport = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "pylint"}},
+    "originalUriBaseIds": {"SRC": {"uri": "file:///ci/work/"}},
+    "results": [
+      {"ruleId": "C0114", "level": "note", "message": {"text": "Missing module docstring"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "app/main%20file.py", "uriBaseId": "SRC"},
+                                           "region": {"startLine": 1, "startColumn": 1}}}]},
+      {"rule": {"id": "R0801"}, "message": {"text": "Similar lines in 2 files"}}
+    ]
+  }]
+}`
+
+const eslintReport = `[
+  {"filePath": "/ci/work/web/app.js", "messages": [
+    {"ruleId": "no-unused-vars", "severity": 2, "message": "'x' is defined but never used.", "line": 4, "column": 7},
+    {"ruleId": null, "fatal": true, "severity": 2, "message": "Parsing error: Unexpected token", "line": 9, "column": 1}
+  ]},
+  {"filePath": "/ci/work/web/ok.js", "messages": []}
+]`
+
+const golangciReport = `{
+  "Issues": [
+    {"FromLinter": "govet", "Text": "printf: wrong type", "Severity": "",
+     "Pos": {"Filename": "cmd/main.go", "Offset": 10, "Line": 12, "Column": 3}}
+  ],
+  "Report": {"Linters": []}
+}`
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		checkstyleReport: external.FormatCheckstyle,
+		sarifReport:      external.FormatSARIF,
+		eslintReport:     external.FormatESLint,
+		golangciReport:   external.FormatGolangCI,
+		"[]":             external.FormatESLint,
+	}
+	for data, want := range tests {
+		got, ok := external.Detect([]byte(data))
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	for _, data := range []string{"", "plain text", `{"a": 1}`, "<html></html>", `[{"a": 1}]`} {
+		_, ok := external.Detect([]byte(data))
+		assert.False(t, ok, data)
+	}
+}
+
+func TestParse_Checkstyle(t *testing.T) {
+	t.Parallel()
+	findings, err := external.Parse(external.FormatCheckstyle, []byte(checkstyleReport))
+	assert.NoError(t, err)
+	assert.Equal(t, []external.Finding{
+		{Tool: "checkstyle", Rule: "errcheck", Severity: external.SeverityError, Path: "pkg/a.go", Line: 3, Column: 2,
+			Message: "Error return value is not checked"},
+		{Tool: "checkstyle", Rule: "golint", Severity: external.SeverityInfo, Path: "pkg/a.go", Line: 7,
+			Message: "comment on exported function"},
+	}, findings)
+}
+
+func TestParse_SARIF(t *testing.T) {
+	t.Parallel()
+	findings, err := external.Parse(external.FormatSARIF, []byte(sarifReport))
+	assert.NoError(t, err)
+	assert.Equal(t, []external.Finding{
+		{Tool: "pylint", Rule: "C0114", Severity: external.SeverityInfo, Path: "/ci/work/app/main file.py",
+			Line: 1, Column: 1, Message: "Missing module docstring"},
+		{Tool: "pylint", Rule: "R0801", Severity: external.SeverityWarning, Message: "Similar lines in 2 files"},
+	}, findings)
+}
+
+func TestParse_ESLint(t *testing.T) {
+	t.Parallel()
+	findings, err := external.Parse(external.FormatESLint, []byte(eslintReport))
+	assert.NoError(t, err)
+	assert.Equal(t, []external.Finding{
+		{Tool: "eslint", Rule: "no-unused-vars", Severity: external.SeverityError, Path: "/ci/work/web/app.js",
+			Line: 4, Column: 7, Message: "'x' is defined but never used."},
+		{Tool: "eslint", Rule: "parse-error", Severity: external.SeverityError, Path: "/ci/work/web/app.js",
+			Line: 9, Column: 1, Message: "Parsing error: Unexpected token"},
+	}, findings)
+}
+
+func TestParse_GolangCI(t *testing.T) {
+	t.Parallel()
+	findings, err := external.Parse(external.FormatGolangCI, []byte(golangciReport))
+	assert.NoError(t, err)
+	assert.Equal(t, []external.Finding{
+		{Tool: "golangci-lint", Rule: "govet", Severity: external.SeverityWarning, Path: "cmd/main.go",
+			Line: 12, Column: 3, Message: "printf: wrong type"},
+	}, findings)
+}
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "pkg/a.go", external.NormalizePath("./pkg/a.go"))
+	assert.Equal(t, "pkg/a.go", external.NormalizePath(`pkg\a.go`))
+	assert.Equal(t, "/src/a b.go", external.NormalizePath("file:///src/a%20b.go"))
+	assert.Equal(t, "C:/src/a.go", external.NormalizePath("file:///C:/src/a.go"))
+	assert.Equal(t, "", external.NormalizePath(""))
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	lint := filepath.Join(dir, "golangci.json")
+	eslint := filepath.Join(dir, "eslint.json")
+	assert.NoError(t, os.WriteFile(lint, []byte(golangciReport), 0o644))
+	assert.NoError(t, os.WriteFile(eslint, []byte(eslintReport), 0o644))
+
+	findings, err := external.Collect([]string{lint, eslint})
+	assert.NoError(t, err)
+	assert.Len(t, findings, 3)
+
+	bad := filepath.Join(dir, "bad.txt")
+	assert.NoError(t, os.WriteFile(bad, []byte("not a report"), 0o644))
+	_, err = external.Collect([]string{lint, bad})
+	assert.Error(t, err)
+	_, err = external.Collect([]string{filepath.Join(dir, "missing.json")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package external
+
+// Форматы отчётов внешних линтеров.
+const (
+	FormatCheckstyle = "checkstyle"
+	FormatSARIF      = "sarif"
+	FormatESLint     = "eslint"
+	FormatGolangCI   = "golangci-lint"
+)
+
+// Уровни серьёзности совпадают с уровнями правил стиля LintVision.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding — замечание внешнего линтера. Path — путь в том виде, в каком
+// он записан в отчёте; при сопоставлении с файлами проекта он
+// приводится к пути относительно корня.
+type Finding struct {
+	Tool     string `json:"tool"`
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
@@ -0,0 +1,54 @@
+package gates
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+//go:embed config.json
+var configData []byte
+
+func init() {
+	var cfg Config
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		logging.Fatal("gates: cannot unmarshal config.json: %v", err)
+	}
+	if err := apply(cfg); err != nil {
+		logging.Fatal("gates: invalid config.json: %v", err)
+	}
+}
+
+// LoadConfig заменяет пороги значениями из JSON-файла того же формата,
+// что и встроенный config.json.
+func LoadConfig(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("gates: cannot read config file %q: %w", filePath, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("gates: invalid JSON in %q: %w", filePath, err)
+	}
+	if err := apply(cfg); err != nil {
+		return fmt.Errorf("gates: invalid config %q: %w", filePath, err)
+	}
+	logging.Info("gates: loaded config from %s", filePath)
+	return nil
+}
+
+func apply(cfg Config) error {
+	for lang, ratio := range cfg.MinCommentRatio {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("min_comment_ratio for %s must be within [0, 1], got %v", lang, ratio)
+		}
+	}
+	if p := cfg.MaxDuplicationPercent; p != nil && (*p < 0 || *p > 100) {
+		return fmt.Errorf("max_duplication_percent must be within [0, 100], got %v", *p)
+	}
+	Current = cfg
+	return nil
+}
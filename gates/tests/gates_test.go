@@ -0,0 +1,56 @@
+package gates_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfigDisabled(t *testing.T) {
+	assert.False(t, gates.Current.Enabled())
+}
+
+func TestLoadConfig(t *testing.T) {
+	saved := gates.Current
+	t.Cleanup(func() { gates.Current = saved })
+
+	cfgFile := filepath.Join(t.TempDir(), "gates.json")
+	assert.NoError(t, os.WriteFile(cfgFile, []byte(`{
+  "max_lines_per_file": 500,
+  "min_comment_ratio": {"Go": 0.1, "*": 0.05}
+}`), 0o644))
+
+	assert.NoError(t, gates.LoadConfig(cfgFile))
+	assert.True(t, gates.Current.Enabled())
+	assert.Equal(t, 500, *gates.Current.MaxLinesPerFile)
+	assert.Nil(t, gates.Current.MaxFileBytes)
+
+	ratio, ok := gates.Current.CommentRatio("Go")
+	assert.True(t, ok)
+	assert.Equal(t, 0.1, ratio)
+	ratio, ok = gates.Current.CommentRatio("Python")
+	assert.True(t, ok)
+	assert.Equal(t, 0.05, ratio)
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	saved := gates.Current
+	t.Cleanup(func() { gates.Current = saved })
+
+	dir := t.TempDir()
+	tests := map[string]string{
+		"ratio.json":   `{"min_comment_ratio": {"Go": 1.5}}`,
+		"percent.json": `{"max_duplication_percent": -1}`,
+		"broken.json":  `{`,
+	}
+	for name, content := range tests {
+		cfgFile := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(cfgFile, []byte(content), 0o644))
+		assert.Error(t, gates.LoadConfig(cfgFile), name)
+	}
+	assert.Error(t, gates.LoadConfig(filepath.Join(dir, "missing.json")))
+	assert.Equal(t, saved, gates.Current)
+}
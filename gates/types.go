@@ -0,0 +1,52 @@
+package gates
+
+// Идентификаторы правил quality gates.
+const (
+	RuleMaxLinesPerFile       = "max-lines-per-file"
+	RuleMinCommentRatio       = "min-comment-ratio"
+	RuleMaxDuplicationPercent = "max-duplication-percent"
+	RuleMaxFileBytes          = "max-file-bytes"
+	RuleMaxUnknownFiles       = "max-unknown-files"
+)
+
+// Config задаёт пороги; правило с пустым (null) порогом не проверяется.
+type Config struct {
+	MaxLinesPerFile *int `json:"max_lines_per_file"`
+	// MinCommentRatio — минимальная доля комментариев comments/(code+comments)
+	// по названию языка; ключ "*" применяется к остальным языкам.
+	MinCommentRatio map[string]float64 `json:"min_comment_ratio"`
+	// MaxDuplicationPercent — допустимый процент строк в точных копиях
+	// файлов (совпадающий хеш), не считая первого экземпляра.
+	MaxDuplicationPercent *float64 `json:"max_duplication_percent"`
+	MaxFileBytes          *int64   `json:"max_file_bytes"`
+	// MaxUnknownFiles — допустимое число файлов категории unknown.
+	MaxUnknownFiles *int `json:"max_unknown_files"`
+}
+
+// Violation — нарушение одного правила; Path пуст для правил уровня проекта.
+type Violation struct {
+	Rule     string  `json:"rule"`
+	Path     string  `json:"path,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Actual   float64 `json:"actual"`
+	Limit    float64 `json:"limit"`
+	Message  string  `json:"message"`
+}
+
+var Current Config
+
+// Enabled сообщает, задан ли хотя бы один порог.
+func (c Config) Enabled() bool {
+	return c.MaxLinesPerFile != nil || len(c.MinCommentRatio) > 0 ||
+		c.MaxDuplicationPercent != nil || c.MaxFileBytes != nil || c.MaxUnknownFiles != nil
+}
+
+// CommentRatio возвращает для языка минимальную долю комментариев
+// и признак того, что правило для него задано.
+func (c Config) CommentRatio(language string) (float64, bool) {
+	if ratio, ok := c.MinCommentRatio[language]; ok {
+		return ratio, true
+	}
+	ratio, ok := c.MinCommentRatio["*"]
+	return ratio, ok
+}
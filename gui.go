@@ -5,14 +5,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/lint"
 	"github.com/rfxxfy/LintVision/logging"
+	"github.com/rfxxfy/LintVision/markers"
 	"github.com/rfxxfy/LintVision/parseurl"
 	"github.com/rfxxfy/LintVision/stats"
 )
@@ -23,14 +29,42 @@ type LintVisionGUI struct {
 	pathEntry      *widget.Entry
 	urlEntry       *widget.Entry
 	outputEntry    *widget.Entry
+	formatSelect   *widget.Select
+	secretsCheck   *widget.Check
+	generatedCheck *widget.Check
+	historyCheck   *widget.Check
+	blameCheck     *widget.Check
+	historyDays    *widget.Entry
+	cloneDepth     *widget.Entry
+	cloneTimeout   *widget.Entry
 	logConfigEntry *widget.Entry
+	importEntry    *widget.Entry
+	coverageEntry  *widget.Entry
 	progressBar    *widget.ProgressBar
 	statusLabel    *widget.Label
 	resultText     *widget.Entry
+	markerFilter   *widget.Select
+	markersList    *widget.List
+	markerRows     []markerRow
+	markerView     []string
+	ownersList     *widget.List
+	ownerRows      []string
+	trendSample    *widget.Select
+	trendEvery     *widget.Entry
+	trendChart     *trendChart
+	trend          stats.Trend
 	isAnalyzing    bool
 	cancelFunc     context.CancelFunc
 }
 
+// allMarkersOption — пункт фильтра маркеров, показывающий все виды.
+const allMarkersOption = "Все"
+
+type markerRow struct {
+	path   string
+	marker markers.Marker
+}
+
 func NewLintVisionGUI() *LintVisionGUI {
 	gui := &LintVisionGUI{
 		app: app.New(),
@@ -54,9 +88,29 @@ func (g *LintVisionGUI) setupUI() {
 	g.outputEntry = widget.NewEntry()
 	g.outputEntry.SetPlaceHolder("Путь к файлу для сохранения результата (опционально)")
 
+	g.formatSelect = widget.NewSelect(stats.Formats(), nil)
+	g.formatSelect.SetSelected(stats.FormatJSON)
+
+	g.secretsCheck = widget.NewCheck("Искать секреты", nil)
+	g.generatedCheck = widget.NewCheck("Без сгенерированных и vendored", nil)
+	g.historyCheck = widget.NewCheck("История git", nil)
+	g.blameCheck = widget.NewCheck("Авторство", nil)
+	g.historyDays = widget.NewEntry()
+	g.historyDays.SetPlaceHolder("вся история")
+	g.cloneDepth = widget.NewEntry()
+	g.cloneDepth.SetPlaceHolder("авто")
+	g.cloneTimeout = widget.NewEntry()
+	g.cloneTimeout.SetPlaceHolder("авто")
+
 	g.logConfigEntry = widget.NewEntry()
 	g.logConfigEntry.SetPlaceHolder("Путь к конфигу логгера (опционально)")
 
+	g.importEntry = widget.NewEntry()
+	g.importEntry.SetPlaceHolder("Отчёты внешних линтеров через запятую (опционально)")
+
+	g.coverageEntry = widget.NewEntry()
+	g.coverageEntry.SetPlaceHolder("Файлы покрытия через запятую: coverage.out, lcov.info, cobertura.xml (опционально)")
+
 	g.progressBar = widget.NewProgressBar()
 	g.progressBar.Hide()
 
@@ -67,24 +121,56 @@ func (g *LintVisionGUI) setupUI() {
 	g.resultText.SetPlaceHolder("Результаты анализа появятся здесь...")
 	g.resultText.Disable()
 
+	g.markerFilter = widget.NewSelect(append([]string{allMarkersOption}, markers.Kinds()...), g.applyMarkerFilter)
+	g.markerFilter.SetSelected(allMarkersOption)
+	g.markersList = widget.NewList(
+		func() int { return len(g.markerView) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(g.markerView[i]) },
+	)
+	g.ownersList = widget.NewList(
+		func() int { return len(g.ownerRows) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(g.ownerRows[i]) },
+	)
+
+	g.trendSample = widget.NewSelect(history.Samples(), nil)
+	g.trendSample.SetSelected(history.SampleEvery)
+	g.trendEvery = widget.NewEntry()
+	g.trendEvery.SetText("10")
+	g.trendChart = newTrendChart()
+
 	selectPathBtn := widget.NewButton("Выбрать директорию", g.selectDirectory)
 	analyzeGitHubBtn := widget.NewButton("Анализ GitHub", g.runGitHubAnalysis)
 	selectOutputBtn := widget.NewButton("Выбрать файл вывода", g.selectOutputFile)
 	selectLogConfigBtn := widget.NewButton("Выбрать конфиг логгера", g.selectLogConfig)
+	selectImportBtn := widget.NewButton("Добавить отчёт", func() { g.addFileTo(g.importEntry) })
+	selectCoverageBtn := widget.NewButton("Добавить файл", func() { g.addFileTo(g.coverageEntry) })
 	analyzeBtn := widget.NewButton("Запустить анализ", g.runAnalysis)
 	cancelBtn := widget.NewButton("Отменить", g.cancelAnalysis)
+	trendBtn := widget.NewButton("Построить по истории", g.runTrend)
+	saveTrendBtn := widget.NewButton("Сохранить CSV/JSON", g.saveTrend)
 
 	pathContainer := container.NewBorder(nil, nil, widget.NewLabel("Директория:"), selectPathBtn, g.pathEntry)
 	urlContainer := container.NewBorder(nil, nil, widget.NewLabel("GitHub URL:"), analyzeGitHubBtn, g.urlEntry)
-	outputContainer := container.NewBorder(nil, nil, widget.NewLabel("Файл вывода:"), selectOutputBtn, g.outputEntry)
+	outputContainer := container.NewBorder(nil, nil, widget.NewLabel("Файл вывода:"),
+		container.NewHBox(g.formatSelect, selectOutputBtn), g.outputEntry)
 	logConfigContainer := container.NewBorder(nil, nil, widget.NewLabel("Конфиг логгера:"), selectLogConfigBtn, g.logConfigEntry)
+	importContainer := container.NewBorder(nil, nil, widget.NewLabel("Отчёты линтеров:"), selectImportBtn, g.importEntry)
+	coverageContainer := container.NewBorder(nil, nil, widget.NewLabel("Покрытие:"), selectCoverageBtn, g.coverageEntry)
 
 	controlsContainer := container.NewVBox(
 		pathContainer,
 		urlContainer,
 		outputContainer,
 		logConfigContainer,
-		container.NewHBox(analyzeBtn, cancelBtn),
+		importContainer,
+		coverageContainer,
+		container.NewHBox(analyzeBtn, cancelBtn, g.secretsCheck, g.generatedCheck, g.historyCheck, g.blameCheck),
+		container.NewGridWithColumns(6,
+			widget.NewLabel("Дней истории:"), g.historyDays,
+			widget.NewLabel("Глубина клона:"), g.cloneDepth,
+			widget.NewLabel("Таймаут клона, мин:"), g.cloneTimeout),
 		g.progressBar,
 		g.statusLabel,
 	)
@@ -94,12 +180,24 @@ func (g *LintVisionGUI) setupUI() {
 		nil,
 		nil,
 		nil,
-		container.NewBorder(
-			widget.NewLabel("Результаты анализа:"),
-			nil,
-			nil,
-			nil,
-			g.resultText,
+		container.NewAppTabs(
+			container.NewTabItem("Результаты анализа", g.resultText),
+			container.NewTabItem("Маркеры", container.NewBorder(
+				container.NewBorder(nil, nil, widget.NewLabel("Вид:"), nil, g.markerFilter),
+				nil,
+				nil,
+				nil,
+				g.markersList,
+			)),
+			container.NewTabItem("Авторы", g.ownersList),
+			container.NewTabItem("Динамика", container.NewBorder(
+				container.NewHBox(widget.NewLabel("Коммиты:"), g.trendSample,
+					widget.NewLabel("шаг:"), g.trendEvery, trendBtn, saveTrendBtn),
+				nil,
+				nil,
+				nil,
+				g.trendChart,
+			)),
 		),
 	)
 
@@ -126,6 +224,9 @@ func (g *LintVisionGUI) selectOutputFile() {
 		}
 		if writer != nil {
 			g.outputEntry.SetText(writer.URI().Path())
+			if format, ok := stats.FormatFromPath(writer.URI().Path()); ok {
+				g.formatSelect.SetSelected(format)
+			}
 			writer.Close()
 		}
 	}, g.mainWindow)
@@ -144,6 +245,46 @@ func (g *LintVisionGUI) selectLogConfig() {
 	}, g.mainWindow)
 }
 
+// addFileTo добавляет выбранный файл к списку путей через запятую в entry.
+func (g *LintVisionGUI) addFileTo(entry *widget.Entry) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, g.mainWindow)
+			return
+		}
+		if reader != nil {
+			paths := splitPaths(entry.Text)
+			entry.SetText(strings.Join(append(paths, reader.URI().Path()), ", "))
+			reader.Close()
+		}
+	}, g.mainWindow)
+}
+
+// splitPaths разбирает список путей через запятую.
+func splitPaths(text string) []string {
+	var paths []string
+	for _, p := range strings.Split(text, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// parseOptionalInt разбирает неотрицательное число из поля ввода;
+// пустое поле означает 0.
+func parseOptionalInt(entry *widget.Entry, name string) (int, error) {
+	text := strings.TrimSpace(entry.Text)
+	if text == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s должно быть неотрицательным числом", name)
+	}
+	return n, nil
+}
+
 func (g *LintVisionGUI) runAnalysis() {
 	if g.isAnalyzing {
 		dialog.ShowError(fmt.Errorf("Анализ уже выполняется. Дождитесь завершения."), g.mainWindow)
@@ -155,7 +296,19 @@ func (g *LintVisionGUI) runAnalysis() {
 
 	path := g.pathEntry.Text
 	output := g.outputEntry.Text
+	format := g.formatSelect.Selected
+	secretsScan := g.secretsCheck.Checked
+	excludeGenerated := g.generatedCheck.Checked
 	logConfig := g.logConfigEntry.Text
+	reports := splitPaths(g.importEntry.Text)
+	profiles := splitPaths(g.coverageEntry.Text)
+	gitHistory := g.historyCheck.Checked
+	blame := g.blameCheck.Checked
+	historyDays, err := parseOptionalInt(g.historyDays, "Число дней истории")
+	if err != nil {
+		dialog.ShowError(err, g.mainWindow)
+		return
+	}
 
 	if path == "" {
 		dialog.ShowError(fmt.Errorf("Укажите директорию для анализа"), g.mainWindow)
@@ -203,7 +356,16 @@ func (g *LintVisionGUI) runAnalysis() {
 		default:
 		}
 
-		result, err := stats.AnalyzeAndSave(expandedPath, output)
+		result, err := stats.AnalyzeAndSaveAs(expandedPath, output, stats.Options{
+			Format:           format,
+			ExcludeGenerated: excludeGenerated,
+			Secrets:          secretsScan,
+			Import:           reports,
+			Coverage:         profiles,
+			History:          gitHistory,
+			HistoryDays:      historyDays,
+			Blame:            blame,
+		})
 		if err != nil {
 			g.progressBar.Hide()
 			g.statusLabel.SetText("Ошибка анализа")
@@ -222,11 +384,84 @@ func (g *LintVisionGUI) runAnalysis() {
 
 		resultText := g.formatResults(result, output)
 		g.resultText.SetText(resultText)
+		g.setMarkers(result)
+		g.setOwners(result)
+
+		g.progressBar.Hide()
+	}()
+}
+
+// runTrend строит тренд строк кода по истории git для директории
+// из поля «Директория».
+func (g *LintVisionGUI) runTrend() {
+	if g.isAnalyzing {
+		dialog.ShowError(fmt.Errorf("Анализ уже выполняется. Дождитесь завершения."), g.mainWindow)
+		return
+	}
+	expandedPath, err := g.expandPath(g.pathEntry.Text)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("Ошибка в пути: %v", err), g.mainWindow)
+		return
+	}
+	every, err := strconv.Atoi(strings.TrimSpace(g.trendEvery.Text))
+	if err != nil || every < 1 {
+		dialog.ShowError(fmt.Errorf("Шаг должен быть положительным числом"), g.mainWindow)
+		return
+	}
+
+	g.isAnalyzing = true
+	g.progressBar.Show()
+	g.progressBar.SetValue(0)
+	g.statusLabel.SetText("Выбор коммитов...")
 
+	go func() {
+		defer func() { g.isAnalyzing = false }()
+		trend, err := stats.ComputeTrend(expandedPath, stats.TrendOptions{
+			Sample: g.trendSample.Selected,
+			Every:  every,
+			Progress: func(done, total int) {
+				g.progressBar.SetValue(float64(done) / float64(total))
+				g.statusLabel.SetText(fmt.Sprintf("Снимков обработано: %d из %d", done, total))
+			},
+		})
 		g.progressBar.Hide()
+		if err != nil {
+			g.statusLabel.SetText("Ошибка построения тренда")
+			dialog.ShowError(fmt.Errorf("Не удалось построить тренд: %v", err), g.mainWindow)
+			return
+		}
+		g.trend = trend
+		g.trendChart.SetTrend(trend)
+		g.statusLabel.SetText(fmt.Sprintf("Тренд построен: %d снимков", len(trend.Points)))
 	}()
 }
 
+// saveTrend сохраняет последний построенный тренд; формат выбирается
+// по расширению файла (.csv — CSV, иначе JSON).
+func (g *LintVisionGUI) saveTrend() {
+	if len(g.trend.Points) == 0 {
+		dialog.ShowError(fmt.Errorf("Сначала постройте тренд"), g.mainWindow)
+		return
+	}
+	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, g.mainWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		format := stats.FormatJSON
+		if strings.EqualFold(writer.URI().Extension(), ".csv") {
+			format = stats.FormatCSV
+		}
+		if err := stats.WriteTrend(writer, g.trend, format); err != nil {
+			dialog.ShowError(fmt.Errorf("Ошибка сохранения тренда: %v", err), g.mainWindow)
+		}
+	}, g.mainWindow)
+}
+
 func (g *LintVisionGUI) cancelAnalysis() {
 	if g.isAnalyzing && g.cancelFunc != nil {
 		g.cancelFunc()
@@ -249,8 +484,9 @@ func (g *LintVisionGUI) expandPath(path string) (string, error) {
 	return path, nil
 }
 
-func (g *LintVisionGUI) formatResults(stats stats.ProjectStats, outputPath string) string {
+func (g *LintVisionGUI) formatResults(ps stats.ProjectStats, outputPath string) string {
 	var result strings.Builder
+	summary := stats.Summarize(ps, 0)
 
 	result.WriteString("Анализ завершен успешно!\n\n")
 
@@ -259,24 +495,205 @@ func (g *LintVisionGUI) formatResults(stats stats.ProjectStats, outputPath strin
 	}
 
 	result.WriteString("=== ОБЩАЯ СТАТИСТИКА ===\n")
-	result.WriteString(fmt.Sprintf("Всего файлов: %d\n", len(stats.Files)))
-	result.WriteString(fmt.Sprintf("Скрытых файлов: %d\n", stats.HiddenFiles))
-	result.WriteString(fmt.Sprintf("Скрытых директорий: %d\n", stats.HiddenDirs))
-	result.WriteString(fmt.Sprintf("Нескрытых директорий: %d\n\n", stats.NonHiddenDirs))
+	result.WriteString(fmt.Sprintf("Всего файлов: %d\n", len(ps.Files)))
+	result.WriteString(fmt.Sprintf("Скрытых файлов: %d\n", ps.HiddenFiles))
+	result.WriteString(fmt.Sprintf("Скрытых директорий: %d\n", ps.HiddenDirs))
+	result.WriteString(fmt.Sprintf("Нескрытых директорий: %d\n", ps.NonHiddenDirs))
+	result.WriteString(fmt.Sprintf("Строк кода: %d (сгенерированных: %d, vendored: %d)\n\n",
+		ps.Totals.Code, ps.GeneratedTotals.Code, ps.VendoredTotals.Code))
+
+	if len(ps.TestRatioByLanguage) > 0 {
+		result.WriteString("=== ТЕСТЫ И РАБОЧИЙ КОД ===\n")
+		result.WriteString(fmt.Sprintf("Строк кода в тестах: %d, вне тестов: %d\n",
+			ps.TestTotals.Code, ps.NonTestTotals.Code))
+		languages := make([]string, 0, len(ps.TestRatioByLanguage))
+		for lang := range ps.TestRatioByLanguage {
+			languages = append(languages, lang)
+		}
+		sort.Strings(languages)
+		for _, lang := range languages {
+			r := ps.TestRatioByLanguage[lang]
+			result.WriteString(fmt.Sprintf("%s: тесты %d / код %d (%.2f)\n", lang, r.TestCode, r.Code, r.Ratio))
+		}
+		result.WriteString("\n")
+	}
 
 	result.WriteString("=== СТАТИСТИКА ПО КАТЕГОРИЯМ ===\n")
-	for category, count := range stats.CategoryCounts {
-		result.WriteString(fmt.Sprintf("%s: %d файлов\n", category, count))
+	for _, c := range summary.Categories {
+		result.WriteString(fmt.Sprintf("%s: %d файлов\n", c.Name, c.Files))
 	}
 	result.WriteString("\n")
 
-	if len(stats.Files) > 0 {
+	if len(summary.Languages) > 0 {
+		result.WriteString("=== СТАТИСТИКА ПО ЯЗЫКАМ ===\n")
+		for _, l := range summary.Languages {
+			result.WriteString(fmt.Sprintf("%s: %d файлов, код: %d, комментарии: %d, пустые: %d\n",
+				l.Name, l.Files, l.Code, l.Comments, l.Blank))
+		}
+		result.WriteString("\n")
+	}
+
+	if ps.Licenses != nil {
+		project := ps.Licenses.Project
+		if project == "" {
+			project = "не определена"
+		}
+		result.WriteString("=== ЛИЦЕНЗИИ ===\n")
+		result.WriteString(fmt.Sprintf("Лицензия проекта: %s\n", project))
+		result.WriteString(fmt.Sprintf("Файлов без SPDX-заголовка: %d\n", len(ps.Licenses.MissingHeader)))
+		for _, c := range ps.Licenses.Conflicts {
+			result.WriteString(fmt.Sprintf("⚠️ %s: %s (ожидается %s)\n", c.Path, c.License, c.Expected))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(ps.Secrets) > 0 {
+		result.WriteString(fmt.Sprintf("=== ВОЗМОЖНЫЕ СЕКРЕТЫ (%d) ===\n", len(ps.Secrets)))
+		for _, f := range ps.Secrets {
+			location := ps.RelPath(f.Path)
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, f.Line)
+			}
+			result.WriteString(fmt.Sprintf("🔑 %s [%s] %s\n", location, f.RuleID, f.Excerpt))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(ps.Violations) > 0 {
+		result.WriteString(fmt.Sprintf("=== НАРУШЕНИЯ QUALITY GATES (%d) ===\n", len(ps.Violations)))
+		for _, v := range ps.Violations {
+			result.WriteString(fmt.Sprintf("⛔ [%s] %s\n", v.Rule, v.Message))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(ps.LintCounts) > 0 {
+		result.WriteString("=== ПРАВИЛА СТИЛЯ ===\n")
+		for _, rule := range lint.RuleIDs() {
+			if count := ps.LintCounts[rule]; count > 0 {
+				result.WriteString(fmt.Sprintf("%s: %d\n", rule, count))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if len(ps.IssueCounts) > 0 || len(ps.UnmatchedIssues) > 0 {
+		result.WriteString("=== ВНЕШНИЕ ЛИНТЕРЫ ===\n")
+		writeCounts(&result, "По инструментам", ps.IssueCounts)
+		writeCounts(&result, "По языкам", ps.IssuesByLanguage)
+		writeCounts(&result, "По директориям", ps.IssuesByDir)
+		if len(ps.UnmatchedIssues) > 0 {
+			result.WriteString(fmt.Sprintf("Не привязано к файлам проекта: %d\n", len(ps.UnmatchedIssues)))
+		}
+		result.WriteString("\n")
+	}
+
+	if ps.CoverageTotals != nil {
+		result.WriteString("=== ПОКРЫТИЕ ТЕСТАМИ ===\n")
+		result.WriteString(fmt.Sprintf("Всего: %.1f%% (покрыто строк: %d, не покрыто: %d)\n",
+			ps.CoverageTotals.Percent, ps.CoverageTotals.Covered, ps.CoverageTotals.Uncovered))
+		writeCoverage(&result, "По языкам", ps.CoverageByLanguage)
+		writeCoverage(&result, "По директориям", ps.CoverageByDir)
+		if untested := stats.UntestedFiles(ps, 10); len(untested) > 0 {
+			result.WriteString("Больше всего непокрытых строк:\n")
+			for _, f := range untested {
+				result.WriteString(fmt.Sprintf("  %s: %d из %d (%.1f%%), код: %d\n", ps.RelPath(f.Path),
+					f.Coverage.Uncovered, f.Coverage.Covered+f.Coverage.Uncovered, f.Coverage.Percent, f.LinesCode))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if ps.History != nil {
+		result.WriteString("=== ИСТОРИЯ ИЗМЕНЕНИЙ ===\n")
+		if ps.History.Since != nil {
+			result.WriteString(fmt.Sprintf("Коммитов с %s: %d\n", ps.History.Since.Format("2006-01-02"), ps.History.Commits))
+		} else {
+			result.WriteString(fmt.Sprintf("Коммитов: %d\n", ps.History.Commits))
+		}
+		if ps.History.Shallow {
+			result.WriteString("⚠️ Неполный клон: старые коммиты не учтены\n")
+		}
+		if hotspots := stats.Hotspots(ps, 10); len(hotspots) > 0 {
+			result.WriteString("Hotspots (часто меняются и велики):\n")
+			for _, f := range hotspots {
+				result.WriteString(fmt.Sprintf("  %s: %.3f (коммитов: %d, +%d/-%d, код: %d)\n", ps.RelPath(f.Path),
+					f.History.Hotspot, f.History.Commits, f.History.LinesAdded, f.History.LinesRemoved, f.LinesCode))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if ps.Ownership != nil {
+		result.WriteString("=== АВТОРСТВО ===\n")
+		result.WriteString(fmt.Sprintf("Строк: %d, авторов: %d, bus factor: %d\n",
+			ps.Ownership.Lines, len(ps.Ownership.Authors), ps.Ownership.BusFactor))
+		for i, a := range ps.Ownership.Authors {
+			if i == 10 {
+				result.WriteString(fmt.Sprintf("  …и ещё %d\n", len(ps.Ownership.Authors)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("  %s: %d (%.1f%%)\n", formatAuthor(a), a.Lines,
+				float64(a.Lines)*100/float64(ps.Ownership.Lines)))
+		}
+		result.WriteString("По директориям — на вкладке «Авторы»\n\n")
+	}
+
+	if ps.CodeOwners != nil {
+		result.WriteString(fmt.Sprintf("=== ВЛАДЕЛЬЦЫ (%s) ===\n", ps.CodeOwners.File))
+		owners := make(map[string]int, len(ps.CodeOwners.ByOwner))
+		for owner, t := range ps.CodeOwners.ByOwner {
+			owners[owner] = t.Code
+		}
+		writeCounts(&result, "Строк кода по владельцам", owners)
+		if n := len(ps.CodeOwners.Unowned); n > 0 {
+			result.WriteString(fmt.Sprintf("Без владельца: %d файлов, %d строк кода\n", n, ps.CodeOwners.UnownedTotals.Code))
+			for i, rel := range ps.CodeOwners.Unowned {
+				if i == 20 {
+					result.WriteString(fmt.Sprintf("  …и ещё %d\n", n-i))
+					break
+				}
+				result.WriteString("  " + rel + "\n")
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if len(ps.MarkerCounts) > 0 {
+		result.WriteString("=== МАРКЕРЫ ===\n")
+		for _, kind := range markers.Kinds() {
+			if count := ps.MarkerCounts[kind]; count > 0 {
+				result.WriteString(fmt.Sprintf("%s: %d\n", kind, count))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if len(ps.Files) > 0 {
 		result.WriteString("=== ДЕТАЛЬНАЯ СТАТИСТИКА ===\n")
-		for _, file := range stats.Files {
+		for _, file := range ps.Files {
 			result.WriteString(fmt.Sprintf("📁 %s\n", filepath.Base(file.Path)))
 			result.WriteString(fmt.Sprintf("   Тип: %s (%s)\n", file.Category, file.Ext))
 			result.WriteString(fmt.Sprintf("   Строк: %d (код: %d, комментарии: %d, пустые: %d)\n",
 				file.LinesTotal, file.LinesCode, file.LinesComments, file.LinesBlank))
+			if file.Coverage != nil {
+				result.WriteString(fmt.Sprintf("   Покрытие: %.1f%% (%d из %d строк)\n", file.Coverage.Percent,
+					file.Coverage.Covered, file.Coverage.Covered+file.Coverage.Uncovered))
+			}
+			if len(file.Issues) > 0 {
+				result.WriteString(fmt.Sprintf("   Замечаний линтеров: %d\n", len(file.Issues)))
+			}
+			if len(file.Owners) > 0 {
+				result.WriteString(fmt.Sprintf("   Владельцы: %s\n", strings.Join(file.Owners, ", ")))
+			}
+			if len(file.Authors) > 0 {
+				result.WriteString(fmt.Sprintf("   Основной автор: %s (%d строк)\n",
+					formatAuthor(file.Authors[0]), file.Authors[0].Lines))
+			}
+			if file.History != nil {
+				result.WriteString(fmt.Sprintf("   Изменён: %s, коммитов: %d\n",
+					file.History.LastModified.Format("2006-01-02"), file.History.Commits))
+			}
 			result.WriteString("\n")
 		}
 	}
@@ -284,6 +701,102 @@ func (g *LintVisionGUI) formatResults(stats stats.ProjectStats, outputPath strin
 	return result.String()
 }
 
+// writeCounts пишет счётчики по ключам в порядке убывания.
+func writeCounts(w *strings.Builder, title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	w.WriteString(title + ":\n")
+	for _, k := range keys {
+		w.WriteString(fmt.Sprintf("  %s: %d\n", k, counts[k]))
+	}
+}
+
+// writeCoverage пишет покрытие по ключам в порядке возрастания процента.
+func writeCoverage(w *strings.Builder, title string, byKey map[string]stats.Coverage) {
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if byKey[keys[i]].Percent != byKey[keys[j]].Percent {
+			return byKey[keys[i]].Percent < byKey[keys[j]].Percent
+		}
+		return keys[i] < keys[j]
+	})
+	w.WriteString(title + ":\n")
+	for _, k := range keys {
+		c := byKey[k]
+		w.WriteString(fmt.Sprintf("  %s: %.1f%% (%d из %d строк)\n", k, c.Percent, c.Covered, c.Covered+c.Uncovered))
+	}
+}
+
+// setOwners заполняет вкладку «Авторы»: директории верхнего уровня,
+// начиная с тех, что держатся на меньшем числе людей.
+func (g *LintVisionGUI) setOwners(ps stats.ProjectStats) {
+	g.ownerRows = g.ownerRows[:0]
+	if ps.Ownership != nil {
+		for _, dir := range ps.Ownership.DirsByRisk() {
+			o := ps.Ownership.ByDir[dir]
+			row := fmt.Sprintf("%s  bus factor: %d  строк: %d ", dir, o.BusFactor, o.Lines)
+			for i, a := range o.Authors {
+				if i == 3 {
+					row += fmt.Sprintf(" …и ещё %d", len(o.Authors)-i)
+					break
+				}
+				row += fmt.Sprintf(" %s %.0f%%", a.Name, float64(a.Lines)*100/float64(o.Lines))
+			}
+			g.ownerRows = append(g.ownerRows, row)
+		}
+	}
+	if g.ownersList != nil {
+		g.ownersList.Refresh()
+	}
+}
+
+// formatAuthor возвращает "Имя <email>".
+func formatAuthor(a history.AuthorLines) string {
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+}
+
+func (g *LintVisionGUI) setMarkers(stats stats.ProjectStats) {
+	g.markerRows = g.markerRows[:0]
+	for _, f := range stats.Files {
+		for _, m := range f.Markers {
+			g.markerRows = append(g.markerRows, markerRow{path: stats.RelPath(f.Path), marker: m})
+		}
+	}
+	g.applyMarkerFilter(g.markerFilter.Selected)
+}
+
+func (g *LintVisionGUI) applyMarkerFilter(kind string) {
+	g.markerView = g.markerView[:0]
+	for _, row := range g.markerRows {
+		if kind != allMarkersOption && row.marker.Kind != kind {
+			continue
+		}
+		text := fmt.Sprintf("%s:%d  %s", row.path, row.marker.Line, row.marker.Kind)
+		if row.marker.Owner != "" {
+			text += "(" + row.marker.Owner + ")"
+		}
+		text += "  " + row.marker.Text
+		g.markerView = append(g.markerView, text)
+	}
+	if g.markersList != nil {
+		g.markersList.Refresh()
+	}
+}
+
 func (g *LintVisionGUI) runGitHubAnalysis() {
 	if g.isAnalyzing {
 		dialog.ShowError(fmt.Errorf("Анализ уже выполняется. Дождитесь завершения."), g.mainWindow)
@@ -295,6 +808,10 @@ func (g *LintVisionGUI) runGitHubAnalysis() {
 
 	url := g.urlEntry.Text
 	output := g.outputEntry.Text
+	format := g.formatSelect.Selected
+	excludeGenerated := g.generatedCheck.Checked
+	gitHistory := g.historyCheck.Checked
+	blame := g.blameCheck.Checked
 	logConfig := g.logConfigEntry.Text
 
 	if url == "" {
@@ -302,6 +819,28 @@ func (g *LintVisionGUI) runGitHubAnalysis() {
 		return
 	}
 
+	historyDays, err := parseOptionalInt(g.historyDays, "Число дней истории")
+	if err != nil {
+		dialog.ShowError(err, g.mainWindow)
+		return
+	}
+	depth, err := parseOptionalInt(g.cloneDepth, "Глубина клона")
+	if err != nil {
+		dialog.ShowError(err, g.mainWindow)
+		return
+	}
+	timeoutMinutes, err := parseOptionalInt(g.cloneTimeout, "Таймаут клонирования")
+	if err != nil {
+		dialog.ShowError(err, g.mainWindow)
+		return
+	}
+	// Без истории и авторства по умолчанию хватает последнего коммита;
+	// с ними при пустой глубине клонируется полная история или, если
+	// задано число дней, только коммиты за этот период.
+	if depth == 0 && !gitHistory && !blame {
+		depth = parseurl.DefaultCloneDepth
+	}
+
 	validationResult := parseurl.ValidateGitHubURL(url)
 	if !validationResult.IsValid {
 		var errorMsg strings.Builder
@@ -356,7 +895,17 @@ func (g *LintVisionGUI) runGitHubAnalysis() {
 		default:
 		}
 
-		result, err := parseurl.AnalyzeRepoFromURL(url)
+		result, err := parseurl.AnalyzeRepoWithOptions(url, parseurl.RepoOptions{
+			Depth:   depth,
+			Timeout: time.Duration(timeoutMinutes) * time.Minute,
+			Analyze: stats.Options{
+				Secrets:          true,
+				ExcludeGenerated: excludeGenerated,
+				History:          gitHistory,
+				HistoryDays:      historyDays,
+				Blame:            blame,
+			},
+		})
 		if err != nil {
 			g.progressBar.Hide()
 			g.statusLabel.SetText("Ошибка анализа GitHub репозитория")
@@ -386,7 +935,7 @@ func (g *LintVisionGUI) runGitHubAnalysis() {
 		g.statusLabel.SetText("Сохранение результатов...")
 
 		if output != "" {
-			if err := stats.SaveStats(result, output); err != nil {
+			if err := stats.SaveStatsAs(result, output, format); err != nil {
 				dialog.ShowError(fmt.Errorf("Ошибка сохранения результатов: %v", err), g.mainWindow)
 			}
 		}
@@ -402,6 +951,8 @@ func (g *LintVisionGUI) runGitHubAnalysis() {
 
 		resultText := g.formatResults(result, output)
 		g.resultText.SetText(resultText)
+		g.setMarkers(result)
+		g.setOwners(result)
 
 		g.progressBar.Hide()
 	}()
@@ -0,0 +1,91 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AuthorLines — число строк файла или директории, последним изменённых
+// автором. Автор определяется по email без учёта регистра; имя и email
+// берутся после применения .mailmap.
+type AuthorLines struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Lines int    `json:"lines"`
+}
+
+// uncommitted — SHA, которым git blame помечает незакоммиченные строки.
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// Blame считает строки файла rel (путь относительно root) по авторам
+// с помощью git blame. Кроме .mailmap в корне репозитория, который git
+// применяет сам, можно указать дополнительный файл mailmap. Строки,
+// которые ещё не закоммичены, не учитываются.
+func Blame(root, rel, mailmap string) ([]AuthorLines, error) {
+	var config []string
+	if mailmap != "" {
+		config = append(config, "mailmap.file="+mailmap)
+	}
+	out, err := gitWithConfig(root, config, "blame", "--line-porcelain", "--", rel)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return parseBlame(out)
+}
+
+// parseBlame разбирает вывод git blame --line-porcelain: для каждой
+// строки файла — заголовок "<sha> <строка> <строка> [<число>]", поля
+// author и author-mail и сама строка, начинающаяся с табуляции.
+func parseBlame(out []byte) ([]AuthorLines, error) {
+	byEmail := make(map[string]*AuthorLines)
+	var sha, name, email string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if sha == "" {
+				return nil, fmt.Errorf("history: malformed blame output: line without header")
+			}
+			if sha != uncommitted {
+				key := strings.ToLower(email)
+				a, ok := byEmail[key]
+				if !ok {
+					a = &AuthorLines{Name: name, Email: email}
+					byEmail[key] = a
+				}
+				a.Lines++
+			}
+			sha, name, email = "", "", ""
+		case strings.HasPrefix(line, "author "):
+			name = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			email = strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+		case sha == "" && len(line) > 40 && line[40] == ' ':
+			sha = line[:40]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	authors := make([]AuthorLines, 0, len(byEmail))
+	for _, a := range byEmail {
+		authors = append(authors, *a)
+	}
+	SortAuthors(authors)
+	return authors, nil
+}
+
+// SortAuthors упорядочивает авторов по убыванию числа строк.
+func SortAuthors(authors []AuthorLines) {
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Lines != authors[j].Lines {
+			return authors[i].Lines > authors[j].Lines
+		}
+		return authors[i].Email < authors[j].Email
+	})
+}
@@ -0,0 +1,108 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// FileChurn — история изменений одного файла. Commits, Added и Removed
+// считаются по коммитам окна анализа, LastModified — по всей доступной
+// истории: файл, не менявшийся в окне, сохраняет дату последнего изменения.
+type FileChurn struct {
+	Commits      int
+	Added        int
+	Removed      int
+	LastModified time.Time
+}
+
+// Churn — результат разбора git log.
+type Churn struct {
+	// Files — история по путям относительно root в slash-нотации.
+	Files map[string]FileChurn
+	// Commits — число коммитов в окне, затронувших root.
+	Commits int
+	// Shallow — репозиторий клонирован с --depth, история неполная.
+	Shallow bool
+}
+
+// commitPrefix отмечает строку заголовка коммита в выводе git log;
+// строки --numstat начинаются с числа или "-", поэтому не путаются с ним.
+const commitPrefix = "commit "
+
+// LoadChurn собирает историю изменений файлов под root из git log.
+// Коммиты старше since (если since не нулевое) учитываются только
+// для LastModified. Переименования не отслеживаются: история файла
+// до переименования относится к старому пути.
+func LoadChurn(root string, since time.Time) (Churn, error) {
+	if !IsRepo(root) {
+		return Churn{}, fmt.Errorf("history: %s is not a git work tree", root)
+	}
+	out, err := git(root, "log", "--no-renames", "--numstat", "--relative",
+		"--format="+commitPrefix+"%H %ct", "--", ".")
+	if err != nil {
+		return Churn{}, fmt.Errorf("history: %w", err)
+	}
+	churn, err := parseLog(out, since)
+	if err != nil {
+		return Churn{}, fmt.Errorf("history: %w", err)
+	}
+	churn.Shallow = IsShallow(root)
+	logging.Info("history: %d files changed in %d commits under %s", len(churn.Files), churn.Commits, root)
+	return churn, nil
+}
+
+// parseLog разбирает вывод git log --numstat с заголовками
+// "commit <sha> <unix-время>". Коммиты идут от новых к старым.
+func parseLog(out []byte, since time.Time) (Churn, error) {
+	churn := Churn{Files: make(map[string]FileChurn)}
+	var when time.Time
+	inWindow := false
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, commitPrefix); ok {
+			fields := strings.Fields(rest)
+			if len(fields) != 2 {
+				return churn, fmt.Errorf("malformed commit header %q", line)
+			}
+			ts, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return churn, fmt.Errorf("malformed commit time %q", line)
+			}
+			when = time.Unix(ts, 0).UTC()
+			inWindow = since.IsZero() || !when.Before(since)
+			if inWindow {
+				churn.Commits++
+			}
+			continue
+		}
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			return churn, fmt.Errorf("malformed numstat line %q", line)
+		}
+		f := churn.Files[parts[2]]
+		if f.LastModified.IsZero() {
+			f.LastModified = when
+		}
+		if inWindow {
+			f.Commits++
+			// Для двоичных файлов git выводит "-" вместо числа строк.
+			added, _ := strconv.Atoi(parts[0])
+			removed, _ := strconv.Atoi(parts[1])
+			f.Added += added
+			f.Removed += removed
+		}
+		churn.Files[parts[2]] = f
+	}
+	return churn, scanner.Err()
+}
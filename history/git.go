@@ -0,0 +1,76 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Timeout ограничивает время одного вызова git.
+var Timeout = 2 * time.Minute
+
+// git запускает git в директории root и возвращает его stdout.
+// core.quotePath отключён, чтобы пути с не-ASCII символами выводились
+// как есть, а не восьмеричными escape-последовательностями.
+func git(root string, args ...string) ([]byte, error) {
+	return gitWithConfig(root, nil, args...)
+}
+
+// gitWithConfig запускает git с дополнительными параметрами
+// конфигурации вида "ключ=значение".
+func gitWithConfig(root string, config []string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	name := args[0]
+	prefix := []string{"-c", "core.quotePath=false"}
+	for _, c := range config {
+		prefix = append(prefix, "-c", c)
+	}
+	args = append(append(prefix, "-C", root), args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("git %s timed out after %v", name, Timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", name, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", name, err)
+	}
+	return out, nil
+}
+
+// IsRepo сообщает, находится ли root внутри рабочей копии git.
+func IsRepo(root string) bool {
+	out, err := git(root, "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// IsShallow сообщает, является ли репозиторий неполным клоном
+// (git clone --depth): история в нём обрезана.
+func IsShallow(root string) bool {
+	out, err := git(root, "rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// TrackedFiles возвращает файлы под root, отслеживаемые git, — пути
+// относительно root в slash-нотации.
+func TrackedFiles(root string) (map[string]bool, error) {
+	out, err := git(root, "ls-files")
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	tracked := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			tracked[line] = true
+		}
+	}
+	return tracked, nil
+}
@@ -0,0 +1,202 @@
+package history
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Способы выбора коммитов для снимков истории.
+const (
+	// SampleEvery — каждый N-й коммит первой родительской линии.
+	SampleEvery = "every"
+	// SampleTags — коммиты, на которые указывают теги.
+	SampleTags = "tags"
+	// SampleWeekly — последний коммит каждой недели.
+	SampleWeekly = "weekly"
+)
+
+// Samples возвращает способы выбора коммитов.
+func Samples() []string {
+	return []string{SampleEvery, SampleWeekly, SampleTags}
+}
+
+// Commit — коммит, выбранный для снимка.
+type Commit struct {
+	SHA  string
+	Time time.Time
+	// Tag — имя тега (для SampleTags).
+	Tag string
+}
+
+// SampleCommits выбирает коммиты для снимков от старых к новым.
+// Для SampleEvery every задаёт шаг (не меньше 1); последний коммит
+// включается всегда, чтобы ряд заканчивался текущим состоянием.
+// Для SampleEvery и SampleWeekly учитываются только коммиты, которые
+// затрагивают root.
+func SampleCommits(root, sample string, every int) ([]Commit, error) {
+	switch sample {
+	case SampleEvery, SampleWeekly:
+	case SampleTags:
+		return tagCommits(root)
+	default:
+		return nil, fmt.Errorf("history: unknown sample %q", sample)
+	}
+	out, err := git(root, "log", "--first-parent", "--reverse", "--format=%H %ct", "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		sha, ts, ok := strings.Cut(line, " ")
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("history: malformed log line %q", line)
+		}
+		commits = append(commits, Commit{SHA: sha, Time: time.Unix(sec, 0).UTC()})
+	}
+	if sample == SampleWeekly {
+		return lastPerWeek(commits), nil
+	}
+	return everyNth(commits, every), nil
+}
+
+func everyNth(commits []Commit, every int) []Commit {
+	every = max(every, 1)
+	var result []Commit
+	for i := 0; i < len(commits); i += every {
+		result = append(result, commits[i])
+	}
+	if n := len(commits); n > 0 && (n-1)%every != 0 {
+		result = append(result, commits[n-1])
+	}
+	return result
+}
+
+// lastPerWeek оставляет последний коммит каждой недели ISO 8601 (UTC).
+func lastPerWeek(commits []Commit) []Commit {
+	var result []Commit
+	for i, c := range commits {
+		if i+1 < len(commits) {
+			y1, w1 := c.Time.ISOWeek()
+			y2, w2 := commits[i+1].Time.ISOWeek()
+			if y1 == y2 && w1 == w2 {
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// tagCommits возвращает коммиты тегов в порядке времени коммита.
+// Аннотированные теги разыменовываются до коммита; теги, указывающие
+// не на коммит, пропускаются.
+func tagCommits(root string) ([]Commit, error) {
+	out, err := git(root, "for-each-ref", "refs/tags",
+		"--format=%(refname:short)%09%(objecttype)%09%(objectname)%09%(committerdate:unix)"+
+			"%09%(*objecttype)%09%(*objectname)%09%(*committerdate:unix)")
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) != 7 {
+			continue
+		}
+		kind, sha, ts := f[1], f[2], f[3]
+		if kind == "tag" {
+			kind, sha, ts = f[4], f[5], f[6]
+		}
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if kind != "commit" || err != nil {
+			continue
+		}
+		commits = append(commits, Commit{SHA: sha, Time: time.Unix(sec, 0).UTC(), Tag: f[0]})
+	}
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	return commits, nil
+}
+
+// Extract записывает в dir содержимое директории root на момент
+// коммита sha с помощью git archive, не трогая рабочую копию. Если root —
+// поддиректория репозитория, git archive сам ограничивается ею.
+// Символические ссылки и подмодули пропускаются.
+func Extract(root, sha, dir string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "archive", "--format=tar", sha)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("history: git archive: %w", err)
+	}
+	extractErr := untar(stdout, dir)
+	// Дочитываем вывод, чтобы git не завис на записи в трубу.
+	io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("history: git archive %s: %s", sha, msg)
+		}
+		return fmt.Errorf("history: git archive %s: %w", sha, err)
+	}
+	return extractErr
+}
+
+// untar распаковывает tar-поток в dir, отклоняя пути вне dir.
+func untar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("history: reading archive: %w", err)
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("history: unsafe path %q in archive", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
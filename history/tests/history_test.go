@@ -0,0 +1,169 @@
+package history_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/internal/gittest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadChurn(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	old := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	gittest.Commit(t, root, old, map[string]string{"a.go": "a\nb\n", "docs/readme.md": "x\n", "bin.dat": "\x00\x01"})
+	gittest.Commit(t, root, recent, map[string]string{"a.go": "a\nc\nd\n", "src/пакет/b.go": "1\n"})
+	gittest.Commit(t, root, recent.Add(time.Hour), map[string]string{"src/пакет/b.go": "2\n", "bin.dat": "\x00\x02"})
+
+	churn, err := history.LoadChurn(root, time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, churn.Commits)
+	assert.False(t, churn.Shallow)
+	assert.Equal(t, history.FileChurn{Commits: 2, Added: 4, Removed: 1, LastModified: recent}, churn.Files["a.go"])
+	assert.Equal(t, history.FileChurn{Commits: 2, Added: 2, Removed: 1, LastModified: recent.Add(time.Hour)}, churn.Files["src/пакет/b.go"])
+	assert.Equal(t, history.FileChurn{Commits: 2, LastModified: recent.Add(time.Hour)}, churn.Files["bin.dat"])
+
+	windowed, err := history.LoadChurn(root, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 2, windowed.Commits)
+	assert.Equal(t, history.FileChurn{Commits: 1, Added: 2, Removed: 1, LastModified: recent}, windowed.Files["a.go"])
+	assert.Equal(t, history.FileChurn{LastModified: old}, windowed.Files["docs/readme.md"],
+		"files unchanged in the window keep their last modification date")
+}
+
+func TestLoadChurn_Subdirectory(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	gittest.Commit(t, root, time.Now(), map[string]string{"app/main.go": "x\n", "other.go": "y\n"})
+
+	churn, err := history.LoadChurn(filepath.Join(root, "app"), time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 1, churn.Commits)
+	assert.Contains(t, churn.Files, "main.go")
+	assert.NotContains(t, churn.Files, "other.go")
+}
+
+func TestLoadChurn_NotARepo(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	_, err := history.LoadChurn(t.TempDir(), time.Time{})
+	assert.ErrorContains(t, err, "not a git work tree")
+}
+
+func TestBlame(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gittest.CommitAs(t, root, "Alice", "alice@old.example", when, map[string]string{"a.go": "1\n2\n3\n"})
+	gittest.CommitAs(t, root, "Bob", "bob@example.com", when, map[string]string{"a.go": "1\nb\n3\nb\n"})
+	gittest.CommitAs(t, root, "alice", "ALICE@new.example", when, map[string]string{"a.go": "1\nb\n3\nb\na\n"})
+	gittest.WriteFiles(t, root, map[string]string{"a.go": "1\nb\n3\nb\na\nuncommitted\n"})
+
+	authors, err := history.Blame(root, "a.go", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []history.AuthorLines{
+		{Name: "Alice", Email: "alice@old.example", Lines: 2},
+		{Name: "Bob", Email: "bob@example.com", Lines: 2},
+		{Name: "alice", Email: "ALICE@new.example", Lines: 1},
+	}, authors)
+
+	mailmap := filepath.Join(t.TempDir(), "mailmap")
+	gittest.WriteFiles(t, filepath.Dir(mailmap), map[string]string{
+		"mailmap": "# aliases\nAlice Smith <alice@new.example> <alice@old.example>\nAlice Smith <alice@new.example>\n",
+	})
+	authors, err = history.Blame(root, "a.go", mailmap)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []history.AuthorLines{
+		{Name: "Alice Smith", Email: "alice@new.example", Lines: 3},
+		{Name: "Bob", Email: "bob@example.com", Lines: 2},
+	}, authors)
+}
+
+func TestTrackedFiles(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	gittest.Commit(t, root, time.Now(), map[string]string{"app/main.go": "x\n", "other.go": "y\n"})
+	gittest.WriteFiles(t, root, map[string]string{"app/new.go": "z\n"})
+
+	tracked, err := history.TrackedFiles(filepath.Join(root, "app"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"main.go": true}, tracked)
+}
+
+func TestSampleCommits(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) // понедельник
+	for i := 0; i < 5; i++ {
+		gittest.Commit(t, root, start.Add(time.Duration(i)*48*time.Hour), map[string]string{"a.go": strings.Repeat("x\n", i+1)})
+	}
+	gittest.Run(t, root, nil, "tag", "v0.1", "HEAD~3")
+	gittest.Run(t, root, []string{"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com"},
+		"tag", "-a", "-m", "release", "v1.0", "HEAD")
+
+	times := func(commits []history.Commit) []time.Time {
+		var result []time.Time
+		for _, c := range commits {
+			result = append(result, c.Time)
+		}
+		return result
+	}
+	day := func(n int) time.Time { return start.Add(time.Duration(n) * 24 * time.Hour) }
+
+	every, err := history.SampleCommits(root, history.SampleEvery, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{day(0), day(6), day(8)}, times(every), "the latest commit is always included")
+
+	weekly, err := history.SampleCommits(root, history.SampleWeekly, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{day(6), day(8)}, times(weekly), "2024-01-07 is the Sunday of the first week")
+
+	tags, err := history.SampleCommits(root, history.SampleTags, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "v0.1", tags[0].Tag)
+		assert.Equal(t, day(2), tags[0].Time)
+		assert.Equal(t, "v1.0", tags[1].Tag)
+		assert.Equal(t, day(8), tags[1].Time, "annotated tags resolve to the commit")
+	}
+
+	_, err = history.SampleCommits(root, "monthly", 0)
+	assert.ErrorContains(t, err, "unknown sample")
+}
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	gittest.Commit(t, root, time.Now(), map[string]string{"app/main.go": "v1\n", "app/pkg/util.go": "u\n", "other.go": "o\n"})
+	gittest.Commit(t, root, time.Now(), map[string]string{"app/main.go": "v2\n"})
+
+	dir := t.TempDir()
+	err := history.Extract(filepath.Join(root, "app"), "HEAD~1", dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "v1\n", string(data))
+	assert.FileExists(t, filepath.Join(dir, "pkg", "util.go"))
+	assert.NoFileExists(t, filepath.Join(dir, "other.go"))
+}
@@ -0,0 +1,72 @@
+// Package gittest содержит общие для тестов помощники, создающие
+// временные git-репозитории.
+package gittest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Init создаёт пустой git-репозиторий во временной директории и
+// возвращает её путь; тест пропускается, если git недоступен.
+func Init(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	Run(t, root, nil, "init", "-q")
+	return root
+}
+
+// Run запускает git в root без системного и пользовательского
+// конфига; env дополняет окружение.
+func Run(t *testing.T, root string, env []string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "HOME="+root)
+	cmd.Env = append(cmd.Env, env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+}
+
+// WriteFiles записывает файлы по путям относительно root; пустое
+// содержимое удаляет файл.
+func WriteFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(root, rel)
+		var err error
+		if content == "" {
+			err = os.Remove(p)
+		} else if err = os.MkdirAll(filepath.Dir(p), 0o755); err == nil {
+			err = os.WriteFile(p, []byte(content), 0o644)
+		}
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+}
+
+// Commit записывает файлы и коммитит их от имени тестового автора.
+func Commit(t *testing.T, root string, when time.Time, files map[string]string) {
+	t.Helper()
+	CommitAs(t, root, "Test", "test@example.com", when, files)
+}
+
+// CommitAs записывает файлы и коммитит их от имени name <email> с датой when.
+func CommitAs(t *testing.T, root, name, email string, when time.Time, files map[string]string) {
+	t.Helper()
+	WriteFiles(t, root, files)
+	date := when.Format(time.RFC3339)
+	env := []string{
+		"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + email, "GIT_COMMITTER_DATE=" + date,
+	}
+	Run(t, root, env, "add", "-A")
+	Run(t, root, env, "commit", "-q", "-m", "change")
+}
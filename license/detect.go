@@ -0,0 +1,225 @@
+package license
+
+import (
+	"embed"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// MinConfidence — минимальное сходство текста с шаблоном, при котором
+// лицензия считается опознанной.
+const MinConfidence = 0.75
+
+const spdxTag = "SPDX-License-Identifier:"
+
+//go:embed corpus/*.txt
+var corpusFS embed.FS
+
+// corpus хранит множества словесных биграмм шаблонов по SPDX-идентификатору.
+// Текст GPL и LGPL не различает "-only" и "-or-later" (это указывается
+// в заголовках файлов), поэтому шаблоны названы вариантом "-only".
+var corpus map[string]map[string]struct{}
+
+// titleLength — сколько первых символов текста проверяется на название
+// лицензии из variants.
+const titleLength = 200
+
+// variants — лицензии, которых нет в корпусе, но текст которых почти
+// совпадает с шаблоном base и отличается названием title.
+var variants = []struct{ base, title, id string }{
+	{base: "GPL-3.0-only", title: "gnu affero general public license", id: "AGPL-3.0-only"},
+}
+
+var wordRe = regexp.MustCompile(`[a-z0-9]+`)
+
+func init() {
+	entries, err := corpusFS.ReadDir("corpus")
+	if err != nil {
+		logging.Fatal("license: cannot read corpus: %v", err)
+	}
+	corpus = make(map[string]map[string]struct{}, len(entries))
+	for _, e := range entries {
+		data, err := corpusFS.ReadFile(path.Join("corpus", e.Name()))
+		if err != nil {
+			logging.Fatal("license: cannot read %s: %v", e.Name(), err)
+		}
+		corpus[strings.TrimSuffix(e.Name(), ".txt")] = shingles(string(data))
+	}
+}
+
+// IsLicenseFile сообщает, похоже ли имя файла на файл лицензии
+// (LICENSE, LICENCE, COPYING, в том числе с суффиксами и расширениями).
+func IsLicenseFile(p string) bool {
+	name := strings.ToUpper(filepath.Base(p))
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Identify сравнивает текст со всеми шаблонами корпуса и возвращает
+// наиболее похожий, если сходство не ниже MinConfidence.
+func Identify(text string) (Match, bool) {
+	sample := shingles(text)
+	var best Match
+	for id, tmpl := range corpus {
+		score := dice(sample, tmpl)
+		if score > best.Confidence || (score == best.Confidence && id < best.SPDX) {
+			best = Match{SPDX: id, Confidence: score}
+		}
+	}
+	if best.Confidence >= MinConfidence {
+		// Название ищется только в начале: текст GPL-3.0 сам упоминает
+		// AGPL в разделе 13.
+		head := strings.ToLower(strings.Join(strings.Fields(text), " "))
+		head = head[:min(len(head), titleLength)]
+		for _, v := range variants {
+			if best.SPDX == v.base && strings.Contains(head, v.title) {
+				best.SPDX = v.id
+			}
+		}
+	}
+	return best, best.Confidence >= MinConfidence
+}
+
+func DetectFile(p string) (Match, bool, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return Match{}, false, err
+	}
+	m, ok := Identify(string(data))
+	return m, ok, nil
+}
+
+// ParseSPDXTag извлекает выражение из строки вида
+// "// SPDX-License-Identifier: MIT OR Apache-2.0".
+func ParseSPDXTag(line string) (string, bool) {
+	idx := strings.Index(line, spdxTag)
+	if idx < 0 {
+		return "", false
+	}
+	expr := strings.TrimSpace(line[idx+len(spdxTag):])
+	expr = strings.TrimSpace(strings.TrimSuffix(expr, "*/"))
+	expr = strings.TrimSpace(strings.TrimSuffix(expr, "-->"))
+	return expr, expr != ""
+}
+
+// Mentions сообщает, упоминает ли SPDX-выражение expr идентификатор id.
+func Mentions(expr, id string) bool {
+	for _, tok := range strings.FieldsFunc(expr, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')'
+	}) {
+		if strings.EqualFold(tok, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps сообщает, есть ли у SPDX-выражений a и b общий
+// идентификатор. Варианты "-only", "-or-later" и "+" одной лицензии
+// считаются одним идентификатором: текст лицензии их не различает.
+func Overlaps(a, b string) bool {
+	for _, x := range IDs(a) {
+		for _, y := range IDs(b) {
+			if strings.EqualFold(baseID(x), baseID(y)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func baseID(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	id = strings.TrimSuffix(id, "-only")
+	return strings.TrimSuffix(id, "-or-later")
+}
+
+// Combine собирает лицензию проекта из лицензий нескольких файлов
+// в корне: разные лицензии (LICENSE-MIT и LICENSE-APACHE) означают
+// выбор между ними и объединяются через OR. LGPL-3.0 дополняет текст
+// GPL-3.0, который по её условиям кладут рядом, поэтому GPL-3.0 при
+// ней не учитывается.
+func Combine(ids []string) string {
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		seen[id] = true
+	}
+	var unique []string
+	for _, id := range ids {
+		if !seen[id] || (id == "GPL-3.0-only" && seen["LGPL-3.0-only"]) {
+			continue
+		}
+		seen[id] = false
+		unique = append(unique, id)
+	}
+	return strings.Join(unique, " OR ")
+}
+
+// IDs возвращает идентификаторы лицензий из SPDX-выражения expr без
+// повторов и в порядке появления: операторы AND, OR и исключения после
+// WITH отбрасываются.
+func IDs(expr string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	afterWith := false
+	for _, tok := range strings.FieldsFunc(expr, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')'
+	}) {
+		switch strings.ToUpper(tok) {
+		case "AND", "OR":
+			continue
+		case "WITH":
+			afterWith = true
+			continue
+		}
+		if afterWith {
+			afterWith = false
+			continue
+		}
+		if !seen[tok] {
+			seen[tok] = true
+			ids = append(ids, tok)
+		}
+	}
+	return ids
+}
+
+// shingles разбивает текст на множество биграмм слов, пропуская строки
+// с копирайтом, которые отличаются в каждом проекте.
+func shingles(text string) map[string]struct{} {
+	var words []string
+	for _, line := range strings.Split(strings.ToLower(text), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "copyright") {
+			continue
+		}
+		words = append(words, wordRe.FindAllString(line, -1)...)
+	}
+	set := make(map[string]struct{}, len(words))
+	for i := 0; i+1 < len(words); i++ {
+		set[words[i]+" "+words[i+1]] = struct{}{}
+	}
+	return set
+}
+
+// dice — коэффициент Сёренсена — Дайса для двух множеств.
+func dice(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
@@ -0,0 +1,185 @@
+package license_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/stretchr/testify/assert"
+)
+
+const mitText = `MIT License
+
+Copyright (c) 2024 Jane Doe
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+const bsd3Text = `Copyright (c) 2020, Example Corp.
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+* Redistributions of source code must retain the above copyright notice, this
+  list of conditions and the following disclaimer.
+* Redistributions in binary form must reproduce the above copyright notice,
+  this list of conditions and the following disclaimer in the documentation
+  and/or other materials provided with the distribution.
+* Neither the name of the copyright holder nor the names of its
+  contributors may be used to endorse or promote products derived from
+  this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+`
+
+func TestIdentify(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOk bool
+	}{
+		{"MIT", mitText, "MIT", true},
+		{"BSD-3-Clause", bsd3Text, "BSD-3-Clause", true},
+		{"MIT with different line wrapping", strings.ReplaceAll(mitText, "\n", " \n "), "MIT", true},
+		{"Not a license", "Just a readme describing how to build the project.", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := license.Identify(tt.text)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got.SPDX)
+				assert.GreaterOrEqual(t, got.Confidence, license.MinConfidence)
+			}
+		})
+	}
+}
+
+// reflow переносит слова текста по count в строке, как делают при
+// копировании лицензии в проект.
+func reflow(text string, count int) string {
+	words := strings.Fields(text)
+	var b strings.Builder
+	for i, w := range words {
+		b.WriteString(w)
+		if (i+1)%count == 0 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+func TestIdentify_Copyleft(t *testing.T) {
+	t.Parallel()
+	for _, id := range []string{"GPL-2.0-only", "GPL-3.0-only", "LGPL-2.1-only", "LGPL-3.0-only", "MPL-2.0"} {
+		data, err := os.ReadFile(filepath.Join("..", "corpus", id+".txt"))
+		if !assert.NoError(t, err, id) {
+			continue
+		}
+		got, ok := license.Identify(reflow(string(data), 9))
+		assert.True(t, ok, id)
+		assert.Equal(t, id, got.SPDX)
+	}
+
+	gpl3, err := os.ReadFile(filepath.Join("..", "corpus", "GPL-3.0-only.txt"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	agpl := strings.ReplaceAll(string(gpl3), "GNU GENERAL PUBLIC LICENSE", "GNU AFFERO GENERAL PUBLIC LICENSE")
+	got, ok := license.Identify(agpl)
+	assert.True(t, ok)
+	assert.Equal(t, "AGPL-3.0-only", got.SPDX, "AGPL is recognised by its title on top of the GPL-3.0 text")
+}
+
+func TestCombine(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", license.Combine(nil))
+	assert.Equal(t, "MIT", license.Combine([]string{"MIT", "MIT"}))
+	assert.Equal(t, "Apache-2.0 OR MIT", license.Combine([]string{"Apache-2.0", "MIT"}))
+	assert.Equal(t, "LGPL-3.0-only", license.Combine([]string{"GPL-3.0-only", "LGPL-3.0-only"}),
+		"LGPL-3.0 ships with the GPL-3.0 text it extends")
+}
+
+func TestOverlaps(t *testing.T) {
+	t.Parallel()
+	assert.True(t, license.Overlaps("MIT", "Apache-2.0 OR MIT"))
+	assert.True(t, license.Overlaps("GPL-3.0-or-later", "GPL-3.0-only"))
+	assert.True(t, license.Overlaps("GPL-2.0+", "GPL-2.0-only"))
+	assert.False(t, license.Overlaps("GPL-3.0-only", "Apache-2.0 OR MIT"))
+	assert.False(t, license.Overlaps("LGPL-3.0-only", "GPL-3.0-only"))
+}
+
+func TestParseSPDXTag(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		line   string
+		want   string
+		wantOk bool
+	}{
+		{"// SPDX-License-Identifier: MIT", "MIT", true},
+		{"# SPDX-License-Identifier: Apache-2.0 OR MIT", "Apache-2.0 OR MIT", true},
+		{"/* SPDX-License-Identifier: BSD-3-Clause */", "BSD-3-Clause", true},
+		{"// SPDX-License-Identifier:", "", false},
+		{"package main", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := license.ParseSPDXTag(tt.line)
+		assert.Equal(t, tt.wantOk, ok, tt.line)
+		assert.Equal(t, tt.want, got, tt.line)
+	}
+}
+
+func TestIsLicenseFile(t *testing.T) {
+	t.Parallel()
+	assert.True(t, license.IsLicenseFile("/repo/LICENSE"))
+	assert.True(t, license.IsLicenseFile("/repo/License.md"))
+	assert.True(t, license.IsLicenseFile("/repo/COPYING.LESSER"))
+	assert.False(t, license.IsLicenseFile("/repo/README.md"))
+}
+
+func TestMentions(t *testing.T) {
+	t.Parallel()
+	assert.True(t, license.Mentions("(MIT OR Apache-2.0)", "MIT"))
+	assert.False(t, license.Mentions("GPL-3.0-only", "MIT"))
+}
+
+func TestIDs(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{"MIT"}, license.IDs("MIT"))
+	assert.Equal(t, []string{"MIT", "Apache-2.0"}, license.IDs("(MIT OR Apache-2.0) AND MIT"))
+	assert.Equal(t, []string{"GPL-2.0-only", "BSD-3-Clause"},
+		license.IDs("GPL-2.0-only WITH Classpath-exception-2.0 or BSD-3-Clause"))
+}
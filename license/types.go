@@ -0,0 +1,27 @@
+package license
+
+// Match — результат сопоставления текста с корпусом лицензий.
+type Match struct {
+	SPDX       string  `json:"spdx"`
+	Confidence float64 `json:"confidence"`
+}
+
+type LicenseFile struct {
+	Path       string  `json:"path"`
+	SPDX       string  `json:"spdx,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+type Conflict struct {
+	Path     string `json:"path"`
+	License  string `json:"license"`
+	Expected string `json:"expected"`
+}
+
+// Report — раздел отчёта о лицензиях проекта.
+type Report struct {
+	Project       string        `json:"project,omitempty"`
+	LicenseFiles  []LicenseFile `json:"license_files,omitempty"`
+	MissingHeader []string      `json:"missing_header,omitempty"`
+	Conflicts     []Conflict    `json:"conflicts,omitempty"`
+}
@@ -0,0 +1,150 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Rule — правило, проверяемое во время построчного обхода файла.
+// Правило не хранит состояние между файлами: для этого есть Context.State.
+type Rule interface {
+	ID() string
+	// CheckLine вызывается для каждой строки файла по порядку.
+	CheckLine(c *Context, l Line)
+	// CheckEnd вызывается после последней строки.
+	CheckEnd(c *Context)
+}
+
+// Describer — необязательный интерфейс правила: краткое описание
+// для отчётов (например, SARIF).
+type Describer interface {
+	Description() string
+}
+
+// Context — состояние одного правила при проверке одного файла.
+type Context struct {
+	Path     string
+	Language string
+	Settings Settings
+	// Last — последняя обработанная строка.
+	Last Line
+	// State — произвольное состояние правила в пределах файла.
+	State any
+
+	rule     string
+	findings *[]Finding
+}
+
+// Report добавляет находку в строке line и колонке column.
+func (c *Context) Report(line, column int, format string, args ...any) {
+	*c.findings = append(*c.findings, Finding{
+		Rule:     c.rule,
+		Severity: c.Settings.Severity,
+		Path:     c.Path,
+		Line:     line,
+		Column:   column,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// registry инициализируется до init() пакета, чтобы встроенный
+// config.json мог сослаться на встроенные правила.
+var registry = func() map[string]Rule {
+	m := make(map[string]Rule, len(builtinRules))
+	for _, r := range builtinRules {
+		m[r.ID()] = r
+	}
+	return m
+}()
+
+// Register добавляет правило; правило с тем же ID заменяется.
+// Новые правила включаются записью в конфиге.
+func Register(r Rule) {
+	registry[r.ID()] = r
+}
+
+func lookup(id string) (Rule, bool) {
+	r, ok := registry[id]
+	return r, ok
+}
+
+// Description возвращает описание правила id или пустую строку,
+// если правило неизвестно или не реализует Describer.
+func Description(id string) string {
+	if d, ok := registry[id].(Describer); ok {
+		return d.Description()
+	}
+	return ""
+}
+
+// RuleIDs возвращает отсортированный список зарегистрированных правил.
+func RuleIDs() []string {
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Checker проверяет один файл включёнными для его языка правилами.
+type Checker struct {
+	rules    []Rule
+	contexts []*Context
+	findings []Finding
+
+	commentToken string
+	suppressions []*suppression
+	// unused — контекст правила unused-suppression, если оно включено.
+	unused *Context
+}
+
+// NewChecker создаёт Checker для файла. commentToken — токен
+// однострочного комментария языка, по которому распознаются директивы
+// подавления; пустой токен отключает их.
+func NewChecker(path, language, commentToken string) *Checker {
+	ch := &Checker{commentToken: commentToken}
+	for _, id := range RuleIDs() {
+		settings, ok := Current.settings(id, language)
+		if !ok {
+			continue
+		}
+		c := &Context{
+			Path: path, Language: language, Settings: settings,
+			rule: id, findings: &ch.findings,
+		}
+		if id == RuleUnusedSuppression {
+			ch.unused = c
+			continue
+		}
+		ch.rules = append(ch.rules, registry[id])
+		ch.contexts = append(ch.contexts, c)
+	}
+	return ch
+}
+
+// Line передаёт правилам очередную строку.
+func (ch *Checker) Line(l Line) {
+	if s, ok := parseSuppression(l, ch.commentToken); ok {
+		ch.suppressions = append(ch.suppressions, s)
+	}
+	for i, r := range ch.rules {
+		r.CheckLine(ch.contexts[i], l)
+		ch.contexts[i].Last = l
+	}
+}
+
+// Finish завершает проверку и возвращает находки в порядке строк.
+func (ch *Checker) Finish() []Finding {
+	for i, r := range ch.rules {
+		r.CheckEnd(ch.contexts[i])
+	}
+	ch.suppress()
+	sort.SliceStable(ch.findings, func(i, j int) bool {
+		if ch.findings[i].Line != ch.findings[j].Line {
+			return ch.findings[i].Line < ch.findings[j].Line
+		}
+		return ch.findings[i].Column < ch.findings[j].Column
+	})
+	return ch.findings
+}
@@ -0,0 +1,68 @@
+package lint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+//go:embed config.json
+var configData []byte
+
+func init() {
+	var cfg Config
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		logging.Fatal("lint: cannot unmarshal config.json: %v", err)
+	}
+	if err := apply(cfg); err != nil {
+		logging.Fatal("lint: invalid config.json: %v", err)
+	}
+}
+
+// LoadConfig заменяет настройки правил значениями из JSON-файла того же
+// формата, что и встроенный config.json. Правила, не упомянутые в файле,
+// отключаются.
+func LoadConfig(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("lint: cannot read config file %q: %w", filePath, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("lint: invalid JSON in %q: %w", filePath, err)
+	}
+	if err := apply(cfg); err != nil {
+		return fmt.Errorf("lint: invalid config %q: %w", filePath, err)
+	}
+	logging.Info("lint: loaded %d rules from %s", len(cfg.Rules), filePath)
+	return nil
+}
+
+func apply(cfg Config) error {
+	for id, rc := range cfg.Rules {
+		if _, ok := lookup(id); !ok {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+		if err := checkSeverity(id, rc.Severity); err != nil {
+			return err
+		}
+		for lang, override := range rc.Languages {
+			if err := checkSeverity(id+"/"+lang, override.Severity); err != nil {
+				return err
+			}
+		}
+	}
+	Current = cfg
+	return nil
+}
+
+func checkSeverity(id, severity string) error {
+	switch severity {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+		return nil
+	}
+	return fmt.Errorf("rule %s: unknown severity %q", id, severity)
+}
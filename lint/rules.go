@@ -0,0 +1,174 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// Идентификаторы встроенных правил.
+const (
+	RuleMaxLineLength      = "max-line-length"
+	RuleTrailingWhitespace = "trailing-whitespace"
+	RuleMixedIndentation   = "mixed-indentation"
+	RuleFinalNewline       = "final-newline"
+	RuleCRLF               = "crlf"
+	RuleMaxFileLines       = "max-file-lines"
+)
+
+var builtinRules = []Rule{
+	maxLineLength{},
+	trailingWhitespace{},
+	mixedIndentation{},
+	finalNewline{},
+	crlf{},
+	maxFileLines{},
+	unusedSuppression{},
+}
+
+// maxLineLength — строка длиннее Settings.Max символов.
+type maxLineLength struct{}
+
+func (maxLineLength) ID() string { return RuleMaxLineLength }
+
+func (maxLineLength) Description() string {
+	return "Строка длиннее допустимого"
+}
+
+func (maxLineLength) CheckLine(c *Context, l Line) {
+	if c.Settings.Max <= 0 {
+		return
+	}
+	if n := utf8.RuneCountInString(l.Text); n > c.Settings.Max {
+		c.Report(l.Number, c.Settings.Max+1, "строка длиной %d символов, допустимо %d", n, c.Settings.Max)
+	}
+}
+
+func (maxLineLength) CheckEnd(*Context) {}
+
+// trailingWhitespace — пробелы или табуляция в конце строки.
+type trailingWhitespace struct{}
+
+func (trailingWhitespace) ID() string { return RuleTrailingWhitespace }
+
+func (trailingWhitespace) Description() string { return "Пробелы в конце строки" }
+
+func (trailingWhitespace) CheckLine(c *Context, l Line) {
+	trimmed := strings.TrimRight(l.Text, " \t")
+	if len(trimmed) < len(l.Text) {
+		c.Report(l.Number, utf8.RuneCountInString(trimmed)+1, "пробелы в конце строки")
+	}
+}
+
+func (trailingWhitespace) CheckEnd(*Context) {}
+
+// mixedIndentation — в отступе смешаны табуляция и пробелы, либо стиль
+// отступа отличается от первого отступа в файле. Строки продолжения
+// блочных комментариев (" * ") не проверяются.
+type mixedIndentation struct{}
+
+func (mixedIndentation) ID() string { return RuleMixedIndentation }
+
+func (mixedIndentation) Description() string {
+	return "Смешанные или непоследовательные отступы"
+}
+
+func (mixedIndentation) CheckLine(c *Context, l Line) {
+	body := strings.TrimLeft(l.Text, " \t")
+	indent := l.Text[:len(l.Text)-len(body)]
+	if indent == "" || body == "" || strings.HasPrefix(body, "*") {
+		return
+	}
+	tabs, spaces := strings.Contains(indent, "\t"), strings.Contains(indent, " ")
+	if tabs && spaces {
+		c.Report(l.Number, 1, "в отступе смешаны табуляция и пробелы")
+		return
+	}
+	style := "пробелами"
+	if tabs {
+		style = "табуляцией"
+	}
+	if c.State == nil {
+		c.State = style
+	} else if c.State != style {
+		c.Report(l.Number, 1, "отступ %s, а в файле принят отступ %s", style, c.State)
+	}
+}
+
+func (mixedIndentation) CheckEnd(*Context) {}
+
+// finalNewline — последняя строка непустого файла без перевода строки.
+type finalNewline struct{}
+
+func (finalNewline) ID() string { return RuleFinalNewline }
+
+func (finalNewline) Description() string {
+	return "Нет перевода строки в конце файла"
+}
+
+func (finalNewline) CheckLine(*Context, Line) {}
+
+func (finalNewline) CheckEnd(c *Context) {
+	if c.Last.Number > 0 && c.Last.Ending == "" {
+		c.Report(c.Last.Number, utf8.RuneCountInString(c.Last.Text)+1, "нет перевода строки в конце файла")
+	}
+}
+
+// crlf — перевод строки CRLF. Сообщается один раз на файл, с первой
+// такой строкой; в репозиториях с CRLF находки отбрасываются при
+// сводке по проекту.
+type crlf struct{}
+
+func (crlf) ID() string { return RuleCRLF }
+
+func (crlf) Description() string { return "Перевод строки CRLF" }
+
+func (crlf) CheckLine(c *Context, l Line) {
+	if l.Ending == "\r\n" && c.State == nil {
+		c.Report(l.Number, utf8.RuneCountInString(l.Text)+1, "перевод строки CRLF вместо LF")
+		c.State = true
+	}
+}
+
+func (crlf) CheckEnd(*Context) {}
+
+// maxFileLines — файл длиннее Settings.Max строк.
+type maxFileLines struct{}
+
+func (maxFileLines) ID() string { return RuleMaxFileLines }
+
+func (maxFileLines) Description() string { return "Файл длиннее допустимого" }
+
+func (maxFileLines) CheckLine(*Context, Line) {}
+
+func (maxFileLines) CheckEnd(c *Context) {
+	if c.Settings.Max > 0 && c.Last.Number > c.Settings.Max {
+		c.Report(c.Settings.Max+1, 1, "файл длиной %d строк, допустимо %d", c.Last.Number, c.Settings.Max)
+	}
+}
+
+// SplitLines — функция разбиения для bufio.Scanner, которая, в отличие
+// от bufio.ScanLines, оставляет перевод строки в токене.
+func SplitLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// ParseLine отделяет перевод строки от токена SplitLines.
+func ParseLine(number int, token string) Line {
+	switch {
+	case strings.HasSuffix(token, "\r\n"):
+		return Line{Number: number, Text: token[:len(token)-2], Ending: "\r\n"}
+	case strings.HasSuffix(token, "\n"):
+		return Line{Number: number, Text: token[:len(token)-1], Ending: "\n"}
+	}
+	return Line{Number: number, Text: strings.TrimSuffix(token, "\r")}
+}
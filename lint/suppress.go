@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Директивы подавления находок. Директива пишется в начале
+// однострочного комментария; за ней через пробел или запятую можно
+// перечислить ID правил, без списка подавляются все правила.
+// Текст после "--" считается пояснением.
+const (
+	// DirectiveIgnore подавляет находки в строке с комментарием.
+	DirectiveIgnore = "lintvision:ignore"
+	// DirectiveIgnoreNextLine подавляет находки в следующей строке.
+	DirectiveIgnoreNextLine = "lintvision:ignore-next-line"
+	// DirectiveIgnoreFile подавляет находки во всём файле.
+	DirectiveIgnoreFile = "lintvision:ignore-file"
+)
+
+// RuleUnusedSuppression сообщает о директивах, которые ничего не подавили.
+const RuleUnusedSuppression = "unused-suppression"
+
+// suppression — директива подавления из комментария.
+type suppression struct {
+	directive string
+	line      int
+	column    int
+	// target — строка, к которой относится директива; 0 — весь файл.
+	target int
+	// rules — ID правил; пусто — все правила.
+	rules []string
+	used  []bool
+}
+
+// matches сообщает, подавляет ли директива находку f, и отмечает
+// использованное правило.
+func (s *suppression) matches(f Finding) bool {
+	if s.target != 0 && s.target != f.Line {
+		return false
+	}
+	if len(s.rules) == 0 {
+		s.used[0] = true
+		return true
+	}
+	for i, id := range s.rules {
+		if id == f.Rule {
+			s.used[i] = true
+			return true
+		}
+	}
+	return false
+}
+
+// parseSuppression ищет директиву в однострочных комментариях строки l.
+// Каждое вхождение token проверяется отдельно, чтобы токен внутри
+// строкового литерала не мешал найти комментарий после кода.
+func parseSuppression(l Line, token string) (*suppression, bool) {
+	if token == "" {
+		return nil, false
+	}
+	offset := 0
+	for {
+		i := strings.Index(l.Text[offset:], token)
+		if i < 0 {
+			return nil, false
+		}
+		start := offset + i + len(token)
+		comment := strings.TrimLeft(l.Text[start:], " \t")
+		if s, ok := parseDirective(comment); ok {
+			s.line = l.Number
+			s.column = utf8.RuneCountInString(l.Text[:len(l.Text)-len(comment)]) + 1
+			switch s.directive {
+			case DirectiveIgnore:
+				s.target = l.Number
+			case DirectiveIgnoreNextLine:
+				s.target = l.Number + 1
+			}
+			return s, true
+		}
+		offset = start
+	}
+}
+
+func parseDirective(comment string) (*suppression, bool) {
+	name, rest, _ := strings.Cut(comment, " ")
+	var directive string
+	switch name {
+	case DirectiveIgnore, DirectiveIgnoreNextLine, DirectiveIgnoreFile:
+		directive = name
+	default:
+		return nil, false
+	}
+	rest, _, _ = strings.Cut(rest, "--")
+	s := &suppression{directive: directive}
+	s.rules = strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	s.used = make([]bool, max(len(s.rules), 1))
+	return s, true
+}
+
+// suppress убирает подавленные находки и добавляет находки
+// unused-suppression для директив и правил, которые ничего не подавили.
+// Такие находки сами не подавляются, иначе их нельзя было бы увидеть.
+func (ch *Checker) suppress() {
+	if len(ch.suppressions) == 0 {
+		return
+	}
+	kept := ch.findings[:0]
+	for _, f := range ch.findings {
+		suppressed := false
+		for _, s := range ch.suppressions {
+			if s.matches(f) {
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			kept = append(kept, f)
+		}
+	}
+	ch.findings = kept
+
+	if ch.unused == nil {
+		return
+	}
+	for _, s := range ch.suppressions {
+		if len(s.rules) == 0 {
+			if !s.used[0] {
+				ch.unused.Report(s.line, s.column, "директива %s ничего не подавляет", s.directive)
+			}
+			continue
+		}
+		for i, id := range s.rules {
+			if !s.used[i] {
+				ch.unused.Report(s.line, s.column, "директива %s не подавляет находок правила %s", s.directive, id)
+			}
+		}
+	}
+}
+
+// unusedSuppression — правило-заглушка, чтобы находки о неиспользованных
+// директивах настраивались в конфиге как обычное правило. Сами находки
+// добавляет Checker после проверки остальных правил.
+type unusedSuppression struct{}
+
+func (unusedSuppression) ID() string { return RuleUnusedSuppression }
+
+func (unusedSuppression) Description() string {
+	return "Директива подавления ничего не подавляет"
+}
+
+func (unusedSuppression) CheckLine(*Context, Line) {}
+
+func (unusedSuppression) CheckEnd(*Context) {}
@@ -0,0 +1,146 @@
+package lint_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+// check прогоняет text через Checker так же, как это делает ComputeFileStats.
+func check(t *testing.T, language, text string) []lint.Finding {
+	t.Helper()
+	return checkWithComments(t, language, "", text)
+}
+
+// checkWithComments — check с распознаванием директив подавления
+// в комментариях, начинающихся с token.
+func checkWithComments(t *testing.T, language, token, text string) []lint.Finding {
+	t.Helper()
+	ch := lint.NewChecker("file", language, token)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Split(lint.SplitLines)
+	n := 0
+	for scanner.Scan() {
+		n++
+		ch.Line(lint.ParseLine(n, scanner.Text()))
+	}
+	assert.NoError(t, scanner.Err())
+	return ch.Finish()
+}
+
+func ruleLines(findings []lint.Finding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Rule)
+	}
+	return result
+}
+
+func TestParseLine(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, lint.Line{Number: 1, Text: "a", Ending: "\r\n"}, lint.ParseLine(1, "a\r\n"))
+	assert.Equal(t, lint.Line{Number: 2, Text: "b", Ending: "\n"}, lint.ParseLine(2, "b\n"))
+	assert.Equal(t, lint.Line{Number: 3, Text: "c"}, lint.ParseLine(3, "c"))
+}
+
+func TestBuiltinRules(t *testing.T) {
+	t.Parallel()
+	text := "def f():\n" +
+		"    x = 1   \n" +
+		"\ty = 2\n" +
+		"    return '" + strings.Repeat("a", 100) + "'\r\n" +
+		"# end"
+	findings := check(t, "Python", text)
+
+	assert.Equal(t, []lint.Finding{
+		{Rule: lint.RuleTrailingWhitespace, Severity: lint.SeverityWarning, Path: "file", Line: 2, Column: 10, Message: "пробелы в конце строки"},
+		{Rule: lint.RuleMixedIndentation, Severity: lint.SeverityWarning, Path: "file", Line: 3, Column: 1, Message: "отступ табуляцией, а в файле принят отступ пробелами"},
+		{Rule: lint.RuleMaxLineLength, Severity: lint.SeverityWarning, Path: "file", Line: 4, Column: 100, Message: "строка длиной 113 символов, допустимо 99"},
+		{Rule: lint.RuleCRLF, Severity: lint.SeverityWarning, Path: "file", Line: 4, Column: 114, Message: "перевод строки CRLF вместо LF"},
+		{Rule: lint.RuleFinalNewline, Severity: lint.SeverityInfo, Path: "file", Line: 5, Column: 6, Message: "нет перевода строки в конце файла"},
+	}, findings)
+}
+
+func TestLanguageOverride(t *testing.T) {
+	t.Parallel()
+	long := strings.Repeat("x", 130) + "\n"
+	assert.Empty(t, check(t, "Go", long))
+	assert.Equal(t, []string{lint.RuleMaxLineLength}, ruleLines(check(t, "JavaScript", long)))
+}
+
+func TestMixedIndentation_BlockComments(t *testing.T) {
+	t.Parallel()
+	text := "/**\n * doc\n */\nfunc f() {\n\treturn\n}\n"
+	assert.Empty(t, check(t, "Go", text))
+	assert.Equal(t, []string{lint.RuleMixedIndentation}, ruleLines(check(t, "Go", "func f() {\n\t  return\n}\n")))
+}
+
+type noFoo struct{}
+
+func (noFoo) ID() string { return "no-foo" }
+
+func (noFoo) CheckLine(c *lint.Context, l lint.Line) {
+	if i := strings.Index(l.Text, "foo"); i >= 0 {
+		c.Report(l.Number, i+1, "foo")
+	}
+}
+
+func (noFoo) CheckEnd(*lint.Context) {}
+
+func TestRegisterAndLoadConfig(t *testing.T) {
+	saved := lint.Current
+	t.Cleanup(func() { lint.Current = saved })
+
+	lint.Register(noFoo{})
+	cfgFile := filepath.Join(t.TempDir(), "lint.json")
+	assert.NoError(t, os.WriteFile(cfgFile, []byte(`{"rules": {"no-foo": {"severity": "error"}}}`), 0o644))
+	assert.NoError(t, lint.LoadConfig(cfgFile))
+
+	findings := check(t, "Go", "a foo  \n")
+	assert.Equal(t, []lint.Finding{
+		{Rule: "no-foo", Severity: lint.SeverityError, Path: "file", Line: 1, Column: 3, Message: "foo"},
+	}, findings)
+
+	assert.NoError(t, os.WriteFile(cfgFile, []byte(`{"rules": {"unknown": {}}}`), 0o644))
+	assert.Error(t, lint.LoadConfig(cfgFile))
+	assert.NoError(t, os.WriteFile(cfgFile, []byte(`{"rules": {"crlf": {"severity": "fatal"}}}`), 0o644))
+	assert.Error(t, lint.LoadConfig(cfgFile))
+}
+
+func TestSuppressions(t *testing.T) {
+	t.Parallel()
+	text := "a := 1 // lintvision:ignore trailing-whitespace  \n" +
+		"// lintvision:ignore-next-line\n" +
+		"b := 2  \n" +
+		"c := 3 // lintvision:ignore crlf -- пояснение  \n" +
+		"d := 4  \n"
+	findings := checkWithComments(t, "Go", "//", text)
+
+	assert.Equal(t, []lint.Finding{
+		{Rule: lint.RuleUnusedSuppression, Severity: lint.SeverityInfo, Path: "file", Line: 4, Column: 11, Message: "директива lintvision:ignore не подавляет находок правила crlf"},
+		{Rule: lint.RuleTrailingWhitespace, Severity: lint.SeverityWarning, Path: "file", Line: 4, Column: 46, Message: "пробелы в конце строки"},
+		{Rule: lint.RuleTrailingWhitespace, Severity: lint.SeverityWarning, Path: "file", Line: 5, Column: 7, Message: "пробелы в конце строки"},
+	}, findings)
+}
+
+func TestSuppressions_File(t *testing.T) {
+	t.Parallel()
+	text := "x = 1  \n# lintvision:ignore-file trailing-whitespace, final-newline\ny = 2  "
+	assert.Empty(t, checkWithComments(t, "Python", "#", text))
+
+	unused := checkWithComments(t, "Python", "#", "# lintvision:ignore-file\nx = 1\n")
+	assert.Equal(t, []string{lint.RuleUnusedSuppression}, ruleLines(unused))
+}
+
+func TestSuppressions_TokenInString(t *testing.T) {
+	t.Parallel()
+	text := "url := \"http://example.com\" // lintvision:ignore  \n"
+	assert.Empty(t, checkWithComments(t, "Go", "//", text))
+	// Без токена комментария директивы не распознаются.
+	assert.Equal(t, []string{lint.RuleTrailingWhitespace}, ruleLines(check(t, "Go", "x // lintvision:ignore  \n")))
+}
@@ -0,0 +1,76 @@
+package lint
+
+// Уровни серьёзности находок.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Config — настройки правил по их ID.
+type Config struct {
+	Rules map[string]RuleConfig `json:"rules"`
+}
+
+// RuleConfig — настройки правила. Languages переопределяет их для
+// отдельных языков (по названию языка); незаданные поля наследуются.
+type RuleConfig struct {
+	Enabled   *bool                 `json:"enabled,omitempty"`
+	Severity  string                `json:"severity,omitempty"`
+	Max       int                   `json:"max,omitempty"`
+	Languages map[string]RuleConfig `json:"languages,omitempty"`
+}
+
+// Settings — итоговые настройки правила для конкретного файла.
+type Settings struct {
+	Severity string
+	Max      int
+}
+
+// Finding — нарушение правила; Line и Column считаются с 1,
+// Column — в символах Unicode.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+}
+
+// Line — строка файла без перевода строки; Ending — "\n", "\r\n"
+// или пустая строка для последней строки без перевода.
+type Line struct {
+	Number int
+	Text   string
+	Ending string
+}
+
+var Current Config
+
+// settings возвращает настройки правила id для языка language
+// и признак того, что правило включено.
+func (c Config) settings(id, language string) (Settings, bool) {
+	rc, ok := c.Rules[id]
+	if !ok {
+		return Settings{}, false
+	}
+	if override, ok := rc.Languages[language]; ok && language != "" {
+		if override.Enabled != nil {
+			rc.Enabled = override.Enabled
+		}
+		if override.Severity != "" {
+			rc.Severity = override.Severity
+		}
+		if override.Max != 0 {
+			rc.Max = override.Max
+		}
+	}
+	if rc.Enabled != nil && !*rc.Enabled {
+		return Settings{}, false
+	}
+	if rc.Severity == "" {
+		rc.Severity = SeverityWarning
+	}
+	return Settings{Severity: rc.Severity, Max: rc.Max}, true
+}
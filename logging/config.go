@@ -9,7 +9,7 @@ import (
 
 type Config struct {
 	Level      string `json:"level"`       // DEBUG, INFO, …
-	Output     string `json:"output"`      // "stdout", "stderr" или путь к файлу
+	Output     string `json:"output"`      // "stderr" (по умолчанию), "stdout" или путь к файлу
 	TimeFormat string `json:"time_format"` // e.g. "2006-01-02 15:04:05"
 	Format     string `json:"format"`      // "text" или "json"
 	Caller     bool   `json:"caller"`      // включить вывод caller
@@ -33,10 +33,10 @@ func LoadConfig(path string) error {
 	SetLevel(lvl)
 
 	switch strings.ToLower(cfg.Output) {
-	case "", "stdout":
-		SetOutput(os.Stdout)
-	case "stderr":
+	case "", "stderr":
 		SetOutput(os.Stderr)
+	case "stdout":
+		SetOutput(os.Stdout)
 	default:
 		f, err := os.OpenFile(cfg.Output,
 			os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
@@ -26,7 +26,9 @@ type Logger struct {
 	caller     bool
 }
 
-var std = New(os.Stdout, INFO)
+// std пишет в stderr, чтобы журнал не смешивался с отчётами, которые
+// печатаются в stdout (JSON, JSON Lines, CSV).
+var std = New(os.Stderr, INFO)
 
 func New(out io.Writer, lvl Level) *Logger {
 	return &Logger{
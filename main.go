@@ -4,18 +4,92 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/rfxxfy/LintVision/classify"
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/lint"
 	"github.com/rfxxfy/LintVision/logging"
+	"github.com/rfxxfy/LintVision/markers"
 	"github.com/rfxxfy/LintVision/stats"
 )
 
+// exitGatesFailed — код выхода при нарушении quality gates; отличается
+// от кода 1, которым завершаются ошибки анализа.
+const exitGatesFailed = 3
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "trend" {
+		os.Exit(runTrend(os.Args[2:]))
+	}
+
 	guiMode := flag.Bool("gui", false, "Запустить в GUI режиме")
 	dir := flag.String("path", ".", "директория для анализа")
 	logCfg := flag.String("log-config", "", "конфиг логгера")
-	out := flag.String("out", "", "файл для сохранения результата JSON")
+	markersCfg := flag.String("markers-config", "", "конфиг маркеров TODO/FIXME")
+	classifyCfg := flag.String("classify-config", "", "конфиг классификации файлов (тесты, сгенерированные, vendored)")
+	lintCfg := flag.String("lint-config", "", "конфиг правил стиля")
+	gatesCfg := flag.String("gates-config", "", "конфиг quality gates (пороги для CI)")
+	baseline := flag.String("baseline", "", "baseline для сравнения с quality gates (по умолчанию "+stats.BaselineFile+" в -path, если есть)")
+	updateBaseline := flag.Bool("update-baseline", false, "сохранить текущие метрики и нарушения как новый baseline")
+	out := flag.String("out", "", "файл для сохранения результата")
+	compare := flag.String("compare", "", "прежний JSON-отчёт для показа изменений в Markdown")
+	excludeGenerated := flag.Bool("exclude-generated", false, "исключить сгенерированные и vendored файлы из отчёта")
+	scanSecrets := flag.Bool("secrets", false, "искать секреты и учётные данные")
+	gitHistory := flag.Bool("history", false, "собрать историю изменений файлов из git (коммиты, добавленные и удалённые строки, hotspot-оценка)")
+	historyDays := flag.Int("history-days", 0, "учитывать историю только за последние N дней (0 — вся история)")
+	blame := flag.Bool("blame", false, "посчитать авторство строк через git blame и bus factor по директориям")
+	mailmap := flag.String("mailmap", "", "дополнительный .mailmap для сопоставления email авторов (.mailmap в корне репозитория учитывается всегда)")
+	secretsAllow := flag.String("secrets-allowlist", "", "allowlist для ложных срабатываний поиска секретов")
+	format := flag.String("format", stats.FormatJSON, "формат файла результата (по умолчанию определяется по расширению -out): "+strings.Join(stats.Formats(), ", "))
+	quiet := flag.Bool("quiet", false, "ничего не выводить в stdout, а в журнал писать только ошибки")
+	showFiles := flag.Bool("files", false, "вывести таблицу по файлам")
+	sortBy := flag.String("sort", stats.SortByLines, "сортировка таблицы файлов: "+strings.Join(stats.SortFields(), ", "))
+	top := flag.Int("top", 0, "показать только N первых файлов (0 — все)")
+	printSchema := flag.Bool("schema", false, "вывести JSON Schema отчёта и выйти")
+	var imports []string
+	flag.Func("import", "отчёт внешнего линтера (checkstyle XML, SARIF, ESLint JSON, golangci-lint JSON); можно указать несколько раз", func(path string) error {
+		imports = append(imports, path)
+		return nil
+	})
+	var coverageProfiles []string
+	flag.Func("coverage", "файл покрытия тестами (Go coverprofile, LCOV, Cobertura XML); можно указать несколько раз", func(path string) error {
+		coverageProfiles = append(coverageProfiles, path)
+		return nil
+	})
 	flag.Parse()
 
+	if *printSchema {
+		schema, err := stats.JSONSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot build schema: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(schema)
+		return
+	}
+
+	formatSet := false
+	flag.Visit(func(f *flag.Flag) { formatSet = formatSet || f.Name == "format" })
+	if !formatSet && *out != "" {
+		if detected, ok := stats.FormatFromPath(*out); ok {
+			*format = detected
+		}
+	}
+
+	// В терминал по умолчанию выводится таблица; явно заданный -format
+	// печатается в stdout, если нет -out, а JSON — всегда, как раньше.
+	printFormat := stats.FormatTable
+	if formatSet && (*out == "" || *format == stats.FormatJSON) {
+		printFormat = *format
+	}
+	if *quiet {
+		printFormat = ""
+	}
+
 	if *guiMode {
 		gui := NewLintVisionGUI()
 		gui.Run()
@@ -28,8 +102,81 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	if *quiet {
+		logging.SetLevel(logging.ERROR)
+	}
+
+	if *markersCfg != "" {
+		if err := markers.LoadConfig(*markersCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load markers config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *classifyCfg != "" {
+		if err := classify.LoadConfig(*classifyCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load classify config: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	if _, err := stats.AnalyzeAndSave(*dir, *out); err != nil {
+	if *lintCfg != "" {
+		if err := lint.LoadConfig(*lintCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load lint config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *gatesCfg != "" {
+		if err := gates.LoadConfig(*gatesCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load gates config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	result, err := stats.AnalyzeAndSaveAs(*dir, *out, stats.Options{
+		Format:           *format,
+		Compare:          *compare,
+		ExcludeGenerated: *excludeGenerated,
+		Secrets:          *scanSecrets,
+		SecretsAllowlist: *secretsAllow,
+		Baseline:         *baseline,
+		UpdateBaseline:   *updateBaseline,
+		Import:           imports,
+		Coverage:         coverageProfiles,
+		History:          *gitHistory,
+		HistoryDays:      *historyDays,
+		Blame:            *blame,
+		Mailmap:          *mailmap,
+		Print:            printFormat,
+		Table: stats.TableOptions{
+			Files:  *showFiles,
+			SortBy: *sortBy,
+			TopN:   *top,
+			Color:  colorEnabled(os.Stdout),
+		},
+	})
+	if err != nil {
 		logging.Fatal("analysis failed: %v", err)
 	}
+	if len(result.Violations) > 0 {
+		if !*quiet {
+			fmt.Fprintf(os.Stderr, "quality gates failed: %d violations\n", len(result.Violations))
+			stats.WriteViolations(os.Stderr, result.Violations)
+		}
+		os.Exit(exitGatesFailed)
+	}
+}
+
+// colorEnabled сообщает, можно ли выводить ANSI-цвета: f — терминал
+// и не задана переменная NO_COLOR.
+func colorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,101 @@
+package markers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+//go:embed config.json
+var configData []byte
+
+var (
+	mu       sync.RWMutex
+	kinds    []string
+	markerRe *regexp.Regexp
+)
+
+var issueRe = regexp.MustCompile(`https?://\S+/issues/\d+|\b[A-Z][A-Z0-9]+-\d+\b|#\d+\b`)
+
+func init() {
+	var cfg Config
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		logging.Fatal("markers: cannot unmarshal config.json: %v", err)
+	}
+	if err := apply(cfg); err != nil {
+		logging.Fatal("markers: invalid config.json: %v", err)
+	}
+}
+
+// LoadConfig заменяет набор искомых маркеров значениями из JSON-файла.
+func LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("markers: cannot read config file %q: %w", path, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("markers: invalid JSON in %q: %w", path, err)
+	}
+	if err := apply(cfg); err != nil {
+		return fmt.Errorf("markers: invalid config %q: %w", path, err)
+	}
+	logging.Info("markers: loaded %d markers from %s", len(cfg.Markers), path)
+	return nil
+}
+
+func apply(cfg Config) error {
+	if len(cfg.Markers) == 0 {
+		return fmt.Errorf("empty markers list")
+	}
+	quoted := make([]string, len(cfg.Markers))
+	for i, m := range cfg.Markers {
+		quoted[i] = regexp.QuoteMeta(m)
+	}
+	// Маркер должен открывать текст комментария: допускаются только
+	// пробелы и оформление вроде "///" или "#!" перед ним.
+	re, err := regexp.Compile(`^[\s/#*!;]*(` + strings.Join(quoted, "|") + `)\b(?:\(([^)]*)\))?:?\s*(.*)`)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	kinds = append([]string(nil), cfg.Markers...)
+	markerRe = re
+	mu.Unlock()
+	return nil
+}
+
+// Kinds возвращает текущий список искомых маркеров.
+func Kinds() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), kinds...)
+}
+
+// Extract ищет маркер в начале текста комментария comment, находящегося
+// на строке line.
+func Extract(comment string, line int) (Marker, bool) {
+	mu.RLock()
+	re := markerRe
+	mu.RUnlock()
+
+	m := re.FindStringSubmatch(comment)
+	if m == nil {
+		return Marker{}, false
+	}
+	text := strings.TrimSpace(m[3])
+	return Marker{
+		Kind:  m[1],
+		Line:  line,
+		Owner: strings.TrimSpace(m[2]),
+		Issue: issueRe.FindString(text),
+		Text:  text,
+	}, true
+}
@@ -0,0 +1,77 @@
+package markers_test
+
+import (
+	"testing"
+
+	"github.com/rfxxfy/LintVision/markers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    markers.Marker
+		wantOk  bool
+	}{
+		{
+			name:    "plain TODO",
+			comment: " TODO: handle errors",
+			want:    markers.Marker{Kind: "TODO", Line: 7, Text: "handle errors"},
+			wantOk:  true,
+		},
+		{
+			name:    "owner and issue",
+			comment: " FIXME(alice): race on shutdown, see #42",
+			want:    markers.Marker{Kind: "FIXME", Line: 7, Owner: "alice", Issue: "#42", Text: "race on shutdown, see #42"},
+			wantOk:  true,
+		},
+		{
+			name:    "tracker key",
+			comment: " HACK work around PROJ-123 until upstream fix",
+			want:    markers.Marker{Kind: "HACK", Line: 7, Issue: "PROJ-123", Text: "work around PROJ-123 until upstream fix"},
+			wantOk:  true,
+		},
+		{
+			name:    "issue URL",
+			comment: " XXX https://github.com/org/repo/issues/9",
+			want:    markers.Marker{Kind: "XXX", Line: 7, Issue: "https://github.com/org/repo/issues/9", Text: "https://github.com/org/repo/issues/9"},
+			wantOk:  true,
+		},
+		{
+			name:    "word containing marker",
+			comment: " TODOS are tracked elsewhere",
+			wantOk:  false,
+		},
+		{
+			name:    "doc comment decoration",
+			comment: "/ TODO: document",
+			want:    markers.Marker{Kind: "TODO", Line: 7, Text: "document"},
+			wantOk:  true,
+		},
+		{
+			name:    "marker in the middle",
+			comment: " this is not a TODO list",
+			wantOk:  false,
+		},
+		{
+			name:    "no marker",
+			comment: " just a comment",
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := markers.Extract(tt.comment, 7)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Error(t *testing.T) {
+	assert.Error(t, markers.LoadConfig("/nonexistent_dir/markers.json"))
+	assert.Equal(t, []string{"TODO", "FIXME", "HACK", "XXX"}, markers.Kinds())
+}
@@ -0,0 +1,14 @@
+package markers
+
+type Config struct {
+	Markers []string `json:"markers"`
+}
+
+// Marker — найденная в комментарии пометка вида TODO(owner): text #123.
+type Marker struct {
+	Kind  string `json:"kind"`
+	Line  int    `json:"line"`
+	Owner string `json:"owner,omitempty"`
+	Issue string `json:"issue,omitempty"`
+	Text  string `json:"text,omitempty"`
+}
@@ -13,7 +13,65 @@ import (
 	"github.com/rfxxfy/LintVision/stats"
 )
 
+// DefaultCloneDepth — глубина клонирования по умолчанию: для подсчёта
+// строк достаточно последнего коммита.
+const DefaultCloneDepth = 1
+
+// Время ожидания git clone по умолчанию: неглубокий клон обычно быстрый,
+// а полная история крупного репозитория может скачиваться долго.
+const (
+	DefaultCloneTimeout = 2 * time.Minute
+	FullCloneTimeout    = 10 * time.Minute
+)
+
+// RepoOptions задаёт клонирование и анализ удалённого репозитория.
+type RepoOptions struct {
+	// Depth — глубина клонирования (git clone --depth); 0 — полная
+	// история. Если при Depth 0 нужна только история изменений за
+	// Analyze.HistoryDays дней (без Analyze.Blame), клонируются лишь
+	// коммиты этого окна (git clone --shallow-since).
+	Depth int
+	// Timeout ограничивает время клонирования; 0 — DefaultCloneTimeout
+	// для неглубокого клона и FullCloneTimeout для полного.
+	Timeout time.Duration
+	Analyze stats.Options
+}
+
+// shallowSince возвращает начало окна истории для git clone
+// --shallow-since или нулевое время, если нужна полная история.
+// Для авторства строк окно не подходит: строки старых коммитов git blame
+// приписал бы граничному коммиту.
+func (o RepoOptions) shallowSince(now time.Time) time.Time {
+	if o.Depth > 0 || !o.Analyze.History || o.Analyze.HistoryDays <= 0 || o.Analyze.Blame {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -o.Analyze.HistoryDays)
+}
+
+// cloneTimeout возвращает время ожидания git clone.
+func (o RepoOptions) cloneTimeout() time.Duration {
+	switch {
+	case o.Timeout > 0:
+		return o.Timeout
+	case o.Depth > 0:
+		return DefaultCloneTimeout
+	default:
+		return FullCloneTimeout
+	}
+}
+
+// AnalyzeRepoFromURL клонирует репозиторий с глубиной DefaultCloneDepth
+// и анализирует его с поиском секретов.
 func AnalyzeRepoFromURL(repoURL string) (stats.ProjectStats, error) {
+	return AnalyzeRepoWithOptions(repoURL, RepoOptions{
+		Depth:   DefaultCloneDepth,
+		Analyze: stats.Options{Secrets: true},
+	})
+}
+
+// AnalyzeRepoWithOptions клонирует репозиторий во временную директорию
+// и анализирует его с параметрами opts.
+func AnalyzeRepoWithOptions(repoURL string, opts RepoOptions) (stats.ProjectStats, error) {
 	logging.Info("AnalyzeRepoFromURL: starting analysis for %s", repoURL)
 
 	tempDir, err := createTempDir()
@@ -30,17 +88,18 @@ func AnalyzeRepoFromURL(repoURL string) (stats.ProjectStats, error) {
 		}
 	}()
 
-	if err := cloneRepo(repoURL, tempDir); err != nil {
+	if err := cloneRepo(repoURL, tempDir, opts); err != nil {
 		logging.Error("AnalyzeRepoFromURL: failed to clone repository %s into %s: %v", repoURL, tempDir, err)
 		return stats.ProjectStats{}, fmt.Errorf("failed to clone repository: %w", err)
 	}
 	logging.Info("AnalyzeRepoFromURL: repository %s successfully cloned into %s", repoURL, tempDir)
 
-	result, err := stats.ComputeProjectStatsFromDir(tempDir)
+	result, err := stats.Analyze(tempDir, opts.Analyze)
 	if err != nil {
 		logging.Error("AnalyzeRepoFromURL: error analyzing files in %s for %s: %v", tempDir, repoURL, err)
 		return stats.ProjectStats{}, fmt.Errorf("error analyzing files: %w", err)
 	}
+	result.Source = repoURL
 	logging.Info("AnalyzeRepoFromURL: repository analysis completed for %s", repoURL)
 
 	return result, nil
@@ -56,21 +115,36 @@ func createTempDir() (string, error) {
 	return tempDir, nil
 }
 
-func cloneRepo(repoURL, destDir string) error {
-	logging.Info("cloneRepo: cloning repository %s into %s", repoURL, destDir)
+// cloneArgs возвращает аргументы git clone; depth <= 0 и нулевое since —
+// полная история.
+func cloneArgs(repoURL, destDir string, depth int, since time.Time) []string {
+	args := []string{"clone"}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	} else if !since.IsZero() {
+		args = append(args, "--shallow-since="+since.UTC().Format(time.RFC3339))
+	}
+	return append(args, repoURL, destDir)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+func cloneRepo(repoURL, destDir string, opts RepoOptions) error {
+	since := opts.shallowSince(time.Now())
+	timeout := opts.cloneTimeout()
+	logging.Info("cloneRepo: cloning repository %s into %s (depth %d, since %v, timeout %v)",
+		repoURL, destDir, opts.Depth, since, timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "git", "clone", "--depth=1", repoURL, destDir)
-	output, err := cmd.CombinedOutput()
-
+	output, err := exec.CommandContext(ctx, "git", cloneArgs(repoURL, destDir, opts.Depth, since)...).CombinedOutput()
+	if err != nil && !since.IsZero() && strings.Contains(string(output), "no commits selected for shallow requests") {
+		// В окне нет коммитов: истории за период нет, хватает последнего коммита.
+		logging.Info("cloneRepo: no commits since %v in %s, cloning the latest commit", since, repoURL)
+		output, err = exec.CommandContext(ctx, "git", cloneArgs(repoURL, destDir, 1, time.Time{})...).CombinedOutput()
+	}
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			logging.Error("cloneRepo: git clone timed out for %s: %v", repoURL, ctx.Err())
-			return fmt.Errorf("git clone timed out after 2 minutes: %w", ctx.Err())
+			return fmt.Errorf("git clone timed out after %v: %w", timeout, ctx.Err())
 		}
-
 		outputStr := string(output)
 		if strings.Contains(outputStr, "Repository not found") || strings.Contains(outputStr, "404") {
 			return fmt.Errorf("репозиторий не найден или не существует: %s", repoURL)
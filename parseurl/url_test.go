@@ -0,0 +1,117 @@
+package parseurl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rfxxfy/LintVision/internal/gittest"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneArgs(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	assert.Equal(t, []string{"clone", "--depth=1", "https://github.com/u/r", "/tmp/r"},
+		cloneArgs("https://github.com/u/r", "/tmp/r", DefaultCloneDepth, time.Time{}))
+	assert.Equal(t, []string{"clone", "--depth=50", "https://github.com/u/r", "/tmp/r"},
+		cloneArgs("https://github.com/u/r", "/tmp/r", 50, since), "an explicit depth wins over the window")
+	assert.Equal(t, []string{"clone", "https://github.com/u/r", "/tmp/r"},
+		cloneArgs("https://github.com/u/r", "/tmp/r", 0, time.Time{}))
+	assert.Equal(t, []string{"clone", "--shallow-since=2024-04-30T21:00:00Z", "https://github.com/u/r", "/tmp/r"},
+		cloneArgs("https://github.com/u/r", "/tmp/r", 0, since))
+}
+
+func TestRepoOptions_ShallowSince(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	window := stats.Options{History: true, HistoryDays: 30}
+	assert.Equal(t, now.AddDate(0, 0, -30), RepoOptions{Analyze: window}.shallowSince(now))
+	assert.True(t, RepoOptions{Depth: 5, Analyze: window}.shallowSince(now).IsZero())
+	assert.True(t, RepoOptions{Analyze: stats.Options{History: true}}.shallowSince(now).IsZero(), "no window means full history")
+	window.Blame = true
+	assert.True(t, RepoOptions{Analyze: window}.shallowSince(now).IsZero(), "blame needs full history")
+}
+
+func TestRepoOptions_CloneTimeout(t *testing.T) {
+	assert.Equal(t, DefaultCloneTimeout, RepoOptions{Depth: DefaultCloneDepth}.cloneTimeout())
+	assert.Equal(t, FullCloneTimeout, RepoOptions{}.cloneTimeout())
+	assert.Equal(t, time.Minute, RepoOptions{Timeout: time.Minute}.cloneTimeout())
+}
+
+// TestAnalyzeRepoWithOptions клонирует локальный репозиторий через
+// file://, поэтому не требует сети.
+func TestAnalyzeRepoWithOptions(t *testing.T) {
+	src := gittest.Init(t)
+	gittest.Commit(t, src, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), map[string]string{"a.go": "package a\n"})
+	gittest.Commit(t, src, time.Now(), map[string]string{"a.go": "package a\n\nvar x = 1\n"})
+	repoURL := "file://" + filepath.ToSlash(src)
+
+	tests := []struct {
+		name    string
+		opts    RepoOptions
+		commits int
+		shallow bool
+	}{
+		{name: "full clone", opts: RepoOptions{Analyze: stats.Options{History: true}}, commits: 2},
+		{name: "shallow clone", opts: RepoOptions{Depth: 1, Analyze: stats.Options{History: true}}, commits: 1, shallow: true},
+		{name: "history window", opts: RepoOptions{Analyze: stats.Options{History: true, HistoryDays: 30}}, commits: 1, shallow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := AnalyzeRepoWithOptions(repoURL, tt.opts)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, repoURL, ps.Source)
+			if assert.NotNil(t, ps.History) {
+				assert.Equal(t, tt.commits, ps.History.Commits)
+				assert.Equal(t, tt.shallow, ps.History.Shallow)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepoWithOptions_EmptyHistoryWindow(t *testing.T) {
+	src := gittest.Init(t)
+	gittest.Commit(t, src, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), map[string]string{"a.go": "package a\n"})
+
+	ps, err := AnalyzeRepoWithOptions("file://"+filepath.ToSlash(src), RepoOptions{
+		Analyze: stats.Options{History: true, HistoryDays: 30},
+	})
+	if !assert.NoError(t, err, "a window without commits falls back to the latest commit") {
+		return
+	}
+	assert.Equal(t, 1, ps.Totals.Files)
+	if assert.NotNil(t, ps.History) {
+		assert.Equal(t, 0, ps.History.Commits)
+	}
+}
+
+func TestAnalyzeRepoWithOptions_ExcludeGenerated(t *testing.T) {
+	license, err := os.ReadFile(filepath.Join("..", "license", "corpus", "MIT.txt"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	src := gittest.Init(t)
+	gittest.Commit(t, src, time.Now(), map[string]string{
+		"LICENSE":           string(license),
+		"main.go":           "package main\n",
+		"vendor/lib/lib.go": "package lib\n",
+		"api/types.pb.go":   "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage api\n",
+	})
+
+	ps, err := AnalyzeRepoWithOptions("file://"+filepath.ToSlash(src), RepoOptions{
+		Depth:   DefaultCloneDepth,
+		Analyze: stats.Options{ExcludeGenerated: true},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, f := range ps.Files {
+		assert.False(t, f.Generated || f.Vendored, f.Path)
+	}
+	if assert.NotNil(t, ps.Licenses) {
+		assert.Equal(t, "MIT", ps.Licenses.Project, "the license is detected before the clone is removed")
+	}
+}
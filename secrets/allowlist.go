@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// AllowlistFile — имя файла allowlist, который ищется в корне проекта.
+const AllowlistFile = ".lintvision-secrets-allow"
+
+type allowEntry struct {
+	pattern string
+	ruleID  string
+}
+
+// Allowlist подавляет ложные срабатывания. Каждая строка файла имеет вид
+// "<glob пути> [rule-id]"; пустые строки и строки с # игнорируются.
+type Allowlist struct {
+	entries []allowEntry
+}
+
+func LoadAllowlist(filePath string) (*Allowlist, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: cannot read allowlist %q: %w", filePath, err)
+	}
+	defer f.Close()
+
+	al := &Allowlist{}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("secrets: %s:%d: expected \"<path-glob> [rule-id]\"", filePath, lineNo)
+		}
+		if _, err := path.Match(fields[0], ""); err != nil {
+			return nil, fmt.Errorf("secrets: %s:%d: bad pattern %q: %w", filePath, lineNo, fields[0], err)
+		}
+		e := allowEntry{pattern: fields[0]}
+		if len(fields) == 2 {
+			e.ruleID = fields[1]
+		}
+		al.entries = append(al.entries, e)
+	}
+	return al, scanner.Err()
+}
+
+// Allows сообщает, подавлена ли находка ruleID в файле relPath
+// (путь относительно корня проекта, через "/").
+func (al *Allowlist) Allows(relPath, ruleID string) bool {
+	if al == nil {
+		return false
+	}
+	for _, e := range al.entries {
+		if e.ruleID != "" && e.ruleID != ruleID {
+			continue
+		}
+		if ok, _ := path.Match(e.pattern, relPath); ok {
+			return true
+		}
+		if ok, _ := path.Match(e.pattern, path.Base(relPath)); ok {
+			return true
+		}
+		if strings.HasSuffix(e.pattern, "/") && strings.HasPrefix(relPath, e.pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package secrets
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+//go:embed config.json
+var configData []byte
+
+func init() {
+	if err := json.Unmarshal(configData, &Rules); err != nil {
+		logging.Fatal("secrets: cannot unmarshal config.json: %v", err)
+	}
+	for i := range Rules {
+		re, err := regexp.Compile(Rules[i].Regex)
+		if err != nil {
+			logging.Fatal("secrets: invalid regex in rule %s: %v", Rules[i].ID, err)
+		}
+		Rules[i].re = re
+	}
+	logging.Info("secrets: loaded %d rules", len(Rules))
+}
@@ -0,0 +1,194 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// minEntropyLen и minEntropy — пороги для строковых литералов,
+	// похожих на случайно сгенерированные ключи.
+	minEntropyLen = 20
+	minEntropy    = 4.0
+
+	maxExcerptLen = 120
+)
+
+var stringLiteralRe = regexp.MustCompile(`"([A-Za-z0-9+/=_\-]{20,})"|'([A-Za-z0-9+/=_\-]{20,})'|` + "`([A-Za-z0-9+/=_\\-]{20,})`")
+
+// IsCredentialFile сообщает, похоже ли имя файла на файл с учётными
+// данными, который не должен попадать в репозиторий.
+func IsCredentialFile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	switch name {
+	case ".env", "credentials", "credentials.json", ".netrc", ".pgpass", ".htpasswd",
+		"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519":
+		return true
+	}
+	if strings.HasPrefix(name, ".env.") {
+		switch strings.TrimPrefix(name, ".env.") {
+		case "example", "sample", "template", "dist":
+			return false
+		}
+		return true
+	}
+	switch filepath.Ext(name) {
+	case ".pem", ".key", ".p12", ".pfx", ".jks", ".keystore":
+		return true
+	}
+	return false
+}
+
+// ScanFile проверяет файл всеми правилами. Бинарные файлы пропускаются.
+func ScanFile(path string) ([]Finding, error) {
+	var findings []Finding
+	if IsCredentialFile(path) {
+		findings = append(findings, Finding{Path: path, RuleID: RuleCredentialFile})
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return findings, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	if head, _ := reader.Peek(8000); bytes.IndexByte(head, 0) >= 0 {
+		return findings, nil
+	}
+
+	// Строки читаются без ограничения длины: в минифицированных файлах
+	// они бывают длиннее любого буфера bufio.Scanner.
+	lineNo := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return findings, err
+		}
+		if line == "" {
+			return findings, nil
+		}
+		lineNo++
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		findings = append(findings, scanLine(path, lineNo, line)...)
+	}
+}
+
+// scanLine проверяет одну строку файла всеми правилами.
+func scanLine(path string, lineNo int, line string) []Finding {
+	// Маскируются все найденные в строке секреты, а не только тот,
+	// о котором сообщает находка: иначе второй секрет попадёт в Excerpt.
+	var spans [][2]int
+	var ruleIDs []string
+	for _, r := range Rules {
+		matches := r.re.FindAllStringSubmatchIndex(line, -1)
+		if matches == nil {
+			continue
+		}
+		ruleIDs = append(ruleIDs, r.ID)
+		for _, loc := range matches {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			spans = append(spans, [2]int{start, end})
+		}
+	}
+	entropySpans := highEntropyLiterals(line)
+	spans = append(spans, entropySpans...)
+	if len(spans) == 0 {
+		return nil
+	}
+	excerpt := redact(line, spans)
+	var findings []Finding
+	for _, id := range ruleIDs {
+		findings = append(findings, Finding{Path: path, Line: lineNo, RuleID: id, Excerpt: excerpt})
+	}
+	if len(ruleIDs) > 0 {
+		return findings
+	}
+	for range entropySpans {
+		findings = append(findings, Finding{Path: path, Line: lineNo, RuleID: RuleHighEntropy, Excerpt: excerpt})
+	}
+	return findings
+}
+
+// Entropy — энтропия Шеннона строки в битах на символ.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	freq := make(map[rune]int)
+	for _, r := range s {
+		freq[r]++
+	}
+	n := float64(len([]rune(s)))
+	var h float64
+	for _, c := range freq {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// highEntropyLiterals возвращает границы строковых литералов line,
+// похожих на случайно сгенерированные ключи.
+func highEntropyLiterals(line string) [][2]int {
+	var spans [][2]int
+	for _, m := range stringLiteralRe.FindAllStringSubmatchIndex(line, -1) {
+		for g := 2; g+1 < len(m); g += 2 {
+			if m[g] < 0 {
+				continue
+			}
+			if value := line[m[g]:m[g+1]]; len(value) >= minEntropyLen && Entropy(value) >= minEntropy {
+				spans = append(spans, [2]int{m[g], m[g+1]})
+			}
+		}
+	}
+	return spans
+}
+
+// redact заменяет каждый участок spans маской, оставляя первые 4
+// символа секрета, и обрезает результат до maxExcerptLen байт.
+func redact(line string, spans [][2]int) string {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var b strings.Builder
+	pos := 0
+	for _, sp := range spans {
+		start, end := max(sp[0], pos), sp[1]
+		if start >= end {
+			continue
+		}
+		secret := line[start:end]
+		keep := 4
+		if len(secret) <= keep*2 {
+			keep = 0
+		}
+		b.WriteString(line[pos:start])
+		b.WriteString(secret[:runeStart(secret, keep)])
+		b.WriteString(strings.Repeat("*", 8))
+		pos = end
+	}
+	b.WriteString(line[pos:])
+	masked := strings.TrimSpace(b.String())
+	if len(masked) > maxExcerptLen {
+		masked = masked[:runeStart(masked, maxExcerptLen)] + "…"
+	}
+	return masked
+}
+
+// runeStart возвращает ближайшую к n слева границу символа UTF-8 в s.
+func runeStart(s string, n int) int {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
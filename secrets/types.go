@@ -0,0 +1,27 @@
+package secrets
+
+import "regexp"
+
+const (
+	RuleHighEntropy    = "high-entropy-string"
+	RuleCredentialFile = "credential-file"
+)
+
+type Rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Regex       string `json:"regex"`
+
+	re *regexp.Regexp
+}
+
+// Finding — предполагаемый секрет. Excerpt содержит строку
+// с замаскированным значением.
+type Finding struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	RuleID  string `json:"rule_id"`
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+var Rules []Rule
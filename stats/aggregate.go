@@ -0,0 +1,193 @@
+package stats
+
+import (
+	"strings"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// LineTotals — суммарное число файлов и строк по набору FileStats.
+type LineTotals struct {
+	Files    int `json:"files"`
+	Lines    int `json:"lines_total"`
+	Code     int `json:"lines_code"`
+	Comments int `json:"lines_comments"`
+	Blank    int `json:"lines_blank"`
+}
+
+func (t *LineTotals) add(f FileStats) {
+	t.Files++
+	t.Lines += f.LinesTotal
+	t.Code += f.LinesCode
+	t.Comments += f.LinesComments
+	t.Blank += f.LinesBlank
+}
+
+// TestRatio — строки кода в тестах и вне их; Ratio = TestCode / Code
+// (0, если нетестового кода нет).
+type TestRatio struct {
+	TestCode int     `json:"test_code"`
+	Code     int     `json:"code"`
+	Ratio    float64 `json:"ratio"`
+}
+
+func (r *TestRatio) add(f FileStats) {
+	if f.Test {
+		r.TestCode += f.LinesCode
+	} else {
+		r.Code += f.LinesCode
+	}
+	if r.Code > 0 {
+		r.Ratio = float64(r.TestCode) / float64(r.Code)
+	}
+}
+
+// topDir возвращает директорию верхнего уровня файла относительно Root
+// ("." для файлов в корне).
+func (ps ProjectStats) topDir(path string) string {
+	rel := ps.RelPath(path)
+	if idx := strings.Index(rel, "/"); idx > 0 {
+		return rel[:idx]
+	}
+	return "."
+}
+
+func addRatio(m map[string]TestRatio, key string, f FileStats) {
+	r := m[key]
+	r.add(f)
+	m[key] = r
+}
+
+// aggregate пересчитывает сводные поля ProjectStats по списку Files.
+func (ps *ProjectStats) aggregate() {
+	ps.resetTotals()
+	for _, f := range ps.Files {
+		ps.addTotals(f)
+	}
+	ps.Licenses = ps.licenseReport()
+	ps.Ownership = ps.ownership()
+}
+
+// resetTotals обнуляет сводные поля перед накоплением через addTotals.
+func (ps *ProjectStats) resetTotals() {
+	ps.CategoryCounts = make(map[string]int)
+	ps.MarkerCounts = nil
+	ps.LintCounts = nil
+	ps.IssueCounts = nil
+	ps.IssuesByLanguage = nil
+	ps.IssuesByDir = nil
+	ps.CoverageTotals = nil
+	ps.CoverageByLanguage = nil
+	ps.CoverageByDir = nil
+	if ps.CodeOwners != nil {
+		ps.CodeOwners.reset()
+	}
+	ps.Totals = LineTotals{}
+	ps.GeneratedTotals = LineTotals{}
+	ps.VendoredTotals = LineTotals{}
+	ps.TestTotals = LineTotals{}
+	ps.NonTestTotals = LineTotals{}
+	ps.TestRatioByLanguage = make(map[string]TestRatio)
+	ps.TestRatioByDir = make(map[string]TestRatio)
+}
+
+// addTotals добавляет один файл к сводным полям, не сохраняя его в Files.
+func (ps *ProjectStats) addTotals(f FileStats) {
+	ps.CategoryCounts[f.Category]++
+	for _, m := range f.Markers {
+		if ps.MarkerCounts == nil {
+			ps.MarkerCounts = make(map[string]int)
+		}
+		ps.MarkerCounts[m.Kind]++
+	}
+	for _, finding := range f.Lint {
+		if ps.LintCounts == nil {
+			ps.LintCounts = make(map[string]int)
+		}
+		ps.LintCounts[finding.Rule]++
+	}
+	if len(f.Issues) > 0 {
+		if ps.IssueCounts == nil {
+			ps.IssueCounts = make(map[string]int)
+			ps.IssuesByLanguage = make(map[string]int)
+			ps.IssuesByDir = make(map[string]int)
+		}
+		for _, issue := range f.Issues {
+			ps.IssueCounts[issue.Tool]++
+		}
+		if f.Language != "" {
+			ps.IssuesByLanguage[f.Language] += len(f.Issues)
+		}
+		ps.IssuesByDir[ps.topDir(f.Path)] += len(f.Issues)
+	}
+	if f.Coverage != nil {
+		if ps.CoverageTotals == nil {
+			ps.CoverageTotals = &Coverage{}
+			ps.CoverageByLanguage = make(map[string]Coverage)
+			ps.CoverageByDir = make(map[string]Coverage)
+		}
+		ps.CoverageTotals.add(*f.Coverage)
+		if f.Language != "" {
+			addCoverage(ps.CoverageByLanguage, f.Language, *f.Coverage)
+		}
+		addCoverage(ps.CoverageByDir, ps.topDir(f.Path), *f.Coverage)
+	}
+	if ps.CodeOwners != nil {
+		ps.CodeOwners.add(ps.RelPath(f.Path), f)
+	}
+	ps.Totals.add(f)
+	if f.Generated {
+		ps.GeneratedTotals.add(f)
+	}
+	if f.Vendored {
+		ps.VendoredTotals.add(f)
+	}
+	if f.Test {
+		ps.TestTotals.add(f)
+	} else {
+		ps.NonTestTotals.add(f)
+	}
+	if f.Language != "" {
+		addRatio(ps.TestRatioByLanguage, f.Language, f)
+		addRatio(ps.TestRatioByDir, ps.topDir(f.Path), f)
+	}
+}
+
+// TotalsExcluding считает итоги, пропуская сгенерированные
+// и/или vendored файлы.
+func (ps ProjectStats) TotalsExcluding(generated, vendored bool) LineTotals {
+	var t LineTotals
+	for _, f := range ps.Files {
+		if (generated && f.Generated) || (vendored && f.Vendored) {
+			continue
+		}
+		t.add(f)
+	}
+	return t
+}
+
+// DropGenerated удаляет из отчёта сгенерированные и vendored файлы
+// вместе с найденными в них зависимостями и пересчитывает итоги.
+func (ps *ProjectStats) DropGenerated() {
+	dropped := make(map[string]bool)
+	kept := ps.Files[:0]
+	for _, f := range ps.Files {
+		if f.Generated || f.Vendored {
+			dropped[f.Path] = true
+			continue
+		}
+		kept = append(kept, f)
+	}
+	ps.Files = kept
+
+	deps := ps.Dependencies[:0]
+	for _, d := range ps.Dependencies {
+		if !dropped[d.Manifest] {
+			deps = append(deps, d)
+		}
+	}
+	ps.Dependencies = deps
+
+	ps.aggregate()
+	logging.Info("DropGenerated: removed %d generated or vendored files", len(dropped))
+}
@@ -0,0 +1,213 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// BaselineFile — имя файла baseline в корне проекта, который
+// используется, если путь не задан явно.
+const BaselineFile = ".lintvision-baseline.json"
+
+// BaselineVersion — версия формата файла baseline.
+const BaselineVersion = 1
+
+// BaselineEntry — метрики одного файла на момент снимка.
+type BaselineEntry struct {
+	LinesTotal    int    `json:"lines_total"`
+	LinesCode     int    `json:"lines_code"`
+	LinesComments int    `json:"lines_comments"`
+	Size          int64  `json:"size"`
+	Hash          string `json:"hash,omitempty"`
+}
+
+// Baseline — снимок метрик и известных нарушений quality gates.
+// Нарушения, уже записанные в baseline, не считаются ошибкой, пока
+// не ухудшатся.
+type Baseline struct {
+	Version    int                      `json:"version"`
+	Created    time.Time                `json:"created"`
+	Files      map[string]BaselineEntry `json:"files"`
+	Violations []gates.Violation        `json:"violations"`
+}
+
+// NewBaseline делает снимок текущих метрик и нарушений ps.
+func NewBaseline(ps ProjectStats) Baseline {
+	b := Baseline{
+		Version:    BaselineVersion,
+		Created:    time.Now().UTC().Truncate(time.Second),
+		Files:      make(map[string]BaselineEntry, len(ps.Files)),
+		Violations: append([]gates.Violation{}, ps.Violations...),
+	}
+	for _, f := range ps.Files {
+		b.Files[ps.RelPath(f.Path)] = BaselineEntry{
+			LinesTotal:    f.LinesTotal,
+			LinesCode:     f.LinesCode,
+			LinesComments: f.LinesComments,
+			Size:          f.Size,
+			Hash:          f.Hash,
+		}
+	}
+	return b
+}
+
+func LoadBaseline(filePath string) (Baseline, error) {
+	var b Baseline
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return b, fmt.Errorf("cannot read baseline %s: %w", filePath, err)
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("invalid baseline %s: %w", filePath, err)
+	}
+	if b.Version > BaselineVersion {
+		return b, fmt.Errorf("baseline %s has version %d, newer than supported %d", filePath, b.Version, BaselineVersion)
+	}
+	return b, nil
+}
+
+func SaveBaseline(b Baseline, filePath string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write baseline %s: %w", filePath, err)
+	}
+	logging.Info("SaveBaseline: written %d files and %d violations to %s", len(b.Files), len(b.Violations), filePath)
+	return nil
+}
+
+// Regressions оставляет из ps.Violations только ухудшения относительно
+// baseline: новые нарушения, файлы, выросшие сильнее записанного,
+// упавшую долю комментариев и новые копии файлов.
+func (b Baseline) Regressions(ps ProjectStats) []gates.Violation {
+	known := make(map[string]gates.Violation, len(b.Violations))
+	for _, v := range b.Violations {
+		known[violationKey(v)] = v
+	}
+
+	var result []gates.Violation
+	for _, v := range ps.Violations {
+		old, ok := known[violationKey(v)]
+		if !ok {
+			result = append(result, v)
+			continue
+		}
+		switch v.Rule {
+		case gates.RuleMinCommentRatio:
+			if v.Actual < old.Actual {
+				result = append(result, v)
+			}
+		case gates.RuleMaxDuplicationPercent:
+			clones := b.newClones(ps)
+			if v.Actual > old.Actual || len(clones) > 0 {
+				result = append(result, v)
+			}
+			result = append(result, clones...)
+		default:
+			if v.Actual > old.Actual {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+func violationKey(v gates.Violation) string {
+	return v.Rule + "\x00" + v.Path + "\x00" + v.Language
+}
+
+// newClones возвращает файлы, которые сейчас совпадают по содержимому
+// с другим файлом, но не были копиями в baseline.
+func (b Baseline) newClones(ps ProjectStats) []gates.Violation {
+	oldCopies := make(map[string]int)
+	for _, e := range b.Files {
+		if e.Hash != "" && e.LinesTotal > 0 {
+			oldCopies[e.Hash]++
+		}
+	}
+	byHash := make(map[string][]string)
+	for _, f := range ps.Files {
+		if f.Hash != "" && f.LinesTotal > 0 {
+			byHash[f.Hash] = append(byHash[f.Hash], ps.RelPath(f.Path))
+		}
+	}
+
+	var result []gates.Violation
+	for hash, paths := range byHash {
+		if len(paths) < 2 {
+			continue
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			if e, ok := b.Files[p]; ok && e.Hash == hash && oldCopies[hash] > 1 {
+				continue
+			}
+			others := make([]string, 0, len(paths)-1)
+			for _, o := range paths {
+				if o != p {
+					others = append(others, o)
+				}
+			}
+			result = append(result, gates.Violation{
+				Rule: gates.RuleMaxDuplicationPercent, Path: p,
+				Message: fmt.Sprintf("%s: новая копия файла %s", p, strings.Join(others, ", ")),
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// baselinePath возвращает явно заданный путь к baseline или BaselineFile
+// в root; ok == false, если baseline не задан и в root его нет.
+func baselinePath(root, explicit string, create bool) (string, bool) {
+	if explicit != "" {
+		return explicit, true
+	}
+	if root == "" {
+		return "", false
+	}
+	candidate := filepath.Join(root, BaselineFile)
+	if create {
+		return candidate, true
+	}
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate, true
+	}
+	return "", false
+}
+
+// applyBaseline при opts.UpdateBaseline сохраняет снимок ps, затем
+// оставляет в ps.Violations только регрессии относительно baseline.
+func applyBaseline(ps *ProjectStats, opts Options) error {
+	path, ok := baselinePath(ps.Root, opts.Baseline, opts.UpdateBaseline)
+	if !ok {
+		return nil
+	}
+	var b Baseline
+	if opts.UpdateBaseline {
+		b = NewBaseline(*ps)
+		if err := SaveBaseline(b, path); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if b, err = LoadBaseline(path); err != nil {
+			return err
+		}
+	}
+	total := len(ps.Violations)
+	ps.Violations = b.Regressions(*ps)
+	logging.Info("applyBaseline: %d regressions among %d violations against %s", len(ps.Violations), total, path)
+	return nil
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"github.com/rfxxfy/LintVision/codeowners"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// CodeOwnersReport — покрытие проекта владельцами из CODEOWNERS.
+// Файл с несколькими владельцами учитывается в ByOwner у каждого из них.
+type CodeOwnersReport struct {
+	// File — путь к CODEOWNERS относительно корня.
+	File    string                `json:"file"`
+	ByOwner map[string]LineTotals `json:"by_owner"`
+	// Unowned — файлы без владельцев (пути относительно корня).
+	Unowned       []string   `json:"unowned,omitempty"`
+	UnownedTotals LineTotals `json:"unowned_totals"`
+}
+
+func (r *CodeOwnersReport) reset() {
+	r.ByOwner = make(map[string]LineTotals)
+	r.Unowned = nil
+	r.UnownedTotals = LineTotals{}
+}
+
+func (r *CodeOwnersReport) add(rel string, f FileStats) {
+	if len(f.Owners) == 0 {
+		r.Unowned = append(r.Unowned, rel)
+		r.UnownedTotals.add(f)
+		return
+	}
+	for _, owner := range f.Owners {
+		t := r.ByOwner[owner]
+		t.add(f)
+		r.ByOwner[owner] = t
+	}
+}
+
+// loadCodeOwners загружает CODEOWNERS из корня проекта. Если файла
+// нет или корень не задан, возвращает nil: владельцы не определяются,
+// а ProjectStats.CodeOwners остаётся пустым.
+func loadCodeOwners(root string) (*codeowners.Ruleset, *CodeOwnersReport) {
+	if root == "" {
+		return nil, nil
+	}
+	rules, loc, err := codeowners.Load(root)
+	if err != nil {
+		logging.Warn("loadCodeOwners: %v", err)
+		return nil, nil
+	}
+	if rules == nil {
+		return nil, nil
+	}
+	report := &CodeOwnersReport{File: loc}
+	report.reset()
+	return rules, report
+}
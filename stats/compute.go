@@ -6,21 +6,53 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/rfxxfy/LintVision/classify"
+	"github.com/rfxxfy/LintVision/deps"
 	"github.com/rfxxfy/LintVision/extensions"
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/rfxxfy/LintVision/lint"
 	"github.com/rfxxfy/LintVision/logging"
+	"github.com/rfxxfy/LintVision/markers"
 )
 
+// maxLineLength ограничивает длину строки, которую может прочитать сканер;
+// минифицированные файлы легко превышают стандартные 64 КБ.
+const maxLineLength = 16 * 1024 * 1024
+
 func ComputeFileStats(path string) (FileStats, error) {
 	ext := filepath.Ext(path)
 	cat := extensions.GetFileCategory(ext)
 
-	fs := FileStats{Path: path, Ext: ext, Category: cat}
+	lang := extensions.GetLanguageName(ext)
+
+	fs := FileStats{
+		Path:      path,
+		Ext:       ext,
+		Category:  cat,
+		Language:  lang,
+		Generated: classify.IsGeneratedPath(path),
+		Vendored:  classify.IsVendored(path),
+		Test:      classify.IsTestFile(path, lang),
+	}
 
-	if h, err := ComputeFileHash(path); err != nil {
+	if h, sum, err := computeFileHashes(path); err != nil {
 		logging.Error("ComputeFileStats: cannot hash %s: %v", path, err)
 		return fs, err
 	} else {
 		fs.Hash = h
+		fs.SHA1 = sum
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		fs.Size = info.Size()
+	}
+
+	if license.IsLicenseFile(path) {
+		if m, ok, err := license.DetectFile(path); err != nil {
+			logging.Warn("ComputeFileStats: cannot read license %s: %v", path, err)
+		} else if ok {
+			fs.LicenseMatch = &m
+		}
 	}
 
 	if cat != "code" && cat != "markup" {
@@ -34,32 +66,57 @@ func ComputeFileStats(path string) (FileStats, error) {
 	}
 	defer f.Close()
 
+	cfg, _ := extensions.GetLanguageConfig(ext)
+	token := cfg.SingleLineCommentToken
+	totalBytes := 0
+
+	checker := lint.NewChecker(path, lang, token)
+
 	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	scanner.Split(lint.SplitLines)
 	for scanner.Scan() {
-		line := scanner.Text()
+		l := lint.ParseLine(fs.LinesTotal+1, scanner.Text())
+		checker.Line(l)
+		line := l.Text
 		trimmed := strings.TrimSpace(line)
 
 		fs.LinesTotal++
+		totalBytes += len(line)
+
+		if !fs.Generated && fs.LinesTotal <= classify.GeneratedHeaderLines &&
+			isCommentLine(trimmed, token) && classify.IsGeneratedHeader(trimmed) {
+			fs.Generated = true
+		}
+
+		if fs.License == "" && fs.LinesTotal <= licenseHeaderLines && isCommentLine(trimmed, token) {
+			if expr, ok := license.ParseSPDXTag(trimmed); ok {
+				fs.License = expr
+			}
+		}
 
 		switch cat {
 		case "code":
-			cfg, _ := extensions.GetLanguageConfig(ext)
-			token := cfg.SingleLineCommentToken
 			switch {
 			case trimmed == "":
 				fs.LinesBlank++
 			case token != "" && strings.HasPrefix(trimmed, token):
 				fs.LinesComments++
+				fs.addMarker(trimmed[len(token):])
 			case token != "" && extensions.IsCommentAfterCode(line, ext):
 				fs.LinesComments++
 				fs.LinesCode++
+				fs.addMarker(line[extensions.CommentIndex(line, ext)+len(token):])
 			default:
 				fs.LinesCode++
 			}
 
 		case "markup":
-			if trimmed == "" {
+			switch {
+			case trimmed == "":
 				fs.LinesBlank++
+			case hashCommentMarkup[ext] && strings.HasPrefix(trimmed, "#"):
+				fs.LinesComments++
 			}
 		}
 	}
@@ -67,24 +124,151 @@ func ComputeFileStats(path string) (FileStats, error) {
 		logging.Error("ComputeFileStats: scanner error in %s: %v", path, err)
 		return fs, err
 	}
+	if classify.IsMinified(totalBytes, fs.LinesTotal) {
+		fs.Generated = true
+	}
+	if !fs.Generated && !fs.Vendored {
+		fs.Lint = checker.Finish()
+	}
 	return fs, nil
 }
 
+// hashCommentMarkup — форматы разметки с комментариями от "#" до конца
+// строки; в остальных разметках комментарии не считаются.
+var hashCommentMarkup = map[string]bool{".yaml": true, ".yml": true, ".toml": true}
+
+// isCommentLine сообщает, начинается ли строка с комментария: однострочного
+// для языка либо блочного (/*, *, <!--, #).
+func isCommentLine(trimmed, token string) bool {
+	if token != "" && strings.HasPrefix(trimmed, token) {
+		return true
+	}
+	for _, prefix := range []string{"/*", "*", "<!--", "#"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// addMarker ищет TODO/FIXME и другие маркеры в тексте комментария
+// текущей строки.
+func (fs *FileStats) addMarker(comment string) {
+	if m, ok := markers.Extract(comment, fs.LinesTotal); ok {
+		fs.Markers = append(fs.Markers, m)
+	}
+}
+
 // ComputeProjectStats аккумулирует FileStats по списку путей и
 // возвращает готовый ProjectStats (без данных о скрытых, они заполняются ниже).
 func ComputeProjectStats(paths []string) (ProjectStats, error) {
+	return computeProjectStats("", paths)
+}
+
+// computeProjectStats — общая часть ComputeProjectStats и
+// ComputeProjectStatsFromDir. Если root задан, признаки, зависящие от
+// директорий (vendored, тесты), определяются по пути относительно root.
+func computeProjectStats(root string, paths []string) (ProjectStats, error) {
 	ps := ProjectStats{
+		SchemaVersion:  SchemaVersion,
+		Root:           root,
 		CategoryCounts: make(map[string]int),
 	}
+	owners, report := loadCodeOwners(root)
+	ps.CodeOwners = report
 	for _, p := range paths {
-		stat, err := ComputeFileStats(p)
+		stat, err := ps.computeFile(p)
 		if err != nil {
 			logging.Error("ComputeProjectStats: error computing %s: %v", p, err)
 			return ps, err
 		}
+		if owners != nil {
+			stat.Owners = owners.Owners(ps.RelPath(p))
+		}
 		ps.Files = append(ps.Files, stat)
-		ps.CategoryCounts[stat.Category]++
 	}
+	dropCRLFInCRLFRepo(ps.Files)
+	ps.Dependencies = deps.Collect(paths)
+	ps.aggregate()
 	logging.Info("ComputeProjectStats: processed %d files", len(ps.Files))
 	return ps, nil
 }
+
+// computeFile считает FileStats для файла проекта; признаки, зависящие
+// от директорий, определяются по пути относительно Root.
+func (ps ProjectStats) computeFile(path string) (FileStats, error) {
+	stat, err := ComputeFileStats(path)
+	if err != nil {
+		return stat, err
+	}
+	if ps.Root != "" {
+		rel := ps.RelPath(path)
+		stat.Vendored = classify.IsVendored(rel)
+		stat.Test = classify.IsTestFile(rel, stat.Language)
+		if stat.Vendored {
+			stat.Lint = nil
+		}
+	}
+	return stat, nil
+}
+
+// dropCRLFInCRLFRepo убирает находки правила crlf, если в проекте
+// файлов с CRLF больше, чем файлов с LF: такой репозиторий использует
+// CRLF намеренно.
+func dropCRLFInCRLFRepo(files []FileStats) {
+	var endings lineEndings
+	for _, f := range files {
+		endings.add(f)
+	}
+	if !endings.crlfConvention() {
+		return
+	}
+	for i := range files {
+		dropLintRule(&files[i], lint.RuleCRLF)
+	}
+	logging.Info("ComputeProjectStats: CRLF is the project convention, crlf findings dropped")
+}
+
+// lineEndings считает файлы с находками crlf и без них; файлы короче
+// двух строк не учитываются.
+type lineEndings struct {
+	crlf, lf int
+}
+
+func (e *lineEndings) add(f FileStats) {
+	if f.LinesTotal < 2 {
+		return
+	}
+	if hasLintRule(f, lint.RuleCRLF) {
+		e.crlf++
+	} else {
+		e.lf++
+	}
+}
+
+// crlfConvention сообщает, что файлов с CRLF больше, чем файлов с LF.
+func (e lineEndings) crlfConvention() bool {
+	return e.crlf > 0 && e.crlf > e.lf
+}
+
+func dropLintRule(f *FileStats, rule string) {
+	kept := f.Lint[:0]
+	for _, finding := range f.Lint {
+		if finding.Rule != rule {
+			kept = append(kept, finding)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	f.Lint = kept
+}
+
+func hasLintRule(f FileStats, rule string) bool {
+	for _, finding := range f.Lint {
+		if finding.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/rfxxfy/LintVision/coverage"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// Coverage — покрытие строк тестами. Учитываются только строки, которые
+// инструмент покрытия считает исполняемыми; Percent = Covered /
+// (Covered + Uncovered) * 100.
+type Coverage struct {
+	Covered   int     `json:"covered"`
+	Uncovered int     `json:"uncovered"`
+	Percent   float64 `json:"percent"`
+}
+
+func (c *Coverage) add(o Coverage) {
+	c.Covered += o.Covered
+	c.Uncovered += o.Uncovered
+	if total := c.Covered + c.Uncovered; total > 0 {
+		c.Percent = float64(c.Covered) * 100 / float64(total)
+	}
+}
+
+func addCoverage(m map[string]Coverage, key string, c Coverage) {
+	v := m[key]
+	v.add(c)
+	m[key] = v
+}
+
+// ApplyCoverage загружает файлы покрытия (Go coverprofile, LCOV,
+// Cobertura XML) и записывает покрытие в FileStats. Файлы, которых нет
+// в профилях, остаются без данных о покрытии, а не считаются
+// непокрытыми: профиль одного языка ничего не говорит о другом.
+func ApplyCoverage(ps *ProjectStats, profiles []string) error {
+	files, err := coverage.Collect(profiles)
+	if err != nil {
+		return err
+	}
+	byFile, unmatched := matchCoverage(newFileMatcher(ps.Root, ps.relPaths()), files)
+	for i := range ps.Files {
+		ps.Files[i].Coverage = byFile[ps.RelPath(ps.Files[i].Path)]
+	}
+	ps.aggregate()
+	logging.Info("ApplyCoverage: %d files in profiles, %d not matched to project files", len(files), unmatched)
+	return nil
+}
+
+// matchCoverage считает покрытие по путям файлов относительно корня
+// и возвращает число файлов профиля, не найденных в проекте. Если
+// несколько записей профиля указывают на один файл, строки объединяются.
+func matchCoverage(m *fileMatcher, files []coverage.File) (map[string]*Coverage, int) {
+	merged := make(map[string]coverage.File)
+	unmatched := 0
+	for _, f := range files {
+		rel, ok := m.match(f.Path)
+		if !ok {
+			unmatched++
+			continue
+		}
+		c, ok := merged[rel]
+		if !ok {
+			c = coverage.File{Path: rel, Lines: make(map[int]int)}
+			merged[rel] = c
+		}
+		for line, hits := range f.Lines {
+			c.Lines[line] += hits
+		}
+	}
+	byFile := make(map[string]*Coverage, len(merged))
+	for rel, f := range merged {
+		covered, uncovered := f.Counts()
+		c := &Coverage{}
+		c.add(Coverage{Covered: covered, Uncovered: uncovered})
+		byFile[rel] = c
+	}
+	return byFile, unmatched
+}
+
+// UntestedFiles возвращает до n файлов с данными о покрытии, у которых
+// больше всего непокрытых строк: крупные непротестированные файлы.
+// При n <= 0 возвращаются все такие файлы.
+func UntestedFiles(ps ProjectStats, n int) []FileStats {
+	var files []FileStats
+	for _, f := range ps.Files {
+		if f.Coverage != nil && f.Coverage.Uncovered > 0 {
+			files = append(files, f)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Coverage.Uncovered != files[j].Coverage.Uncovered {
+			return files[i].Coverage.Uncovered > files[j].Coverage.Uncovered
+		}
+		return files[i].LinesCode > files[j].LinesCode
+	})
+	if n > 0 && len(files) > n {
+		files = files[:n]
+	}
+	return files
+}
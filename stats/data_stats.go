@@ -1,21 +1,137 @@
 package stats
 
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/deps"
+	"github.com/rfxxfy/LintVision/external"
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/rfxxfy/LintVision/lint"
+	"github.com/rfxxfy/LintVision/markers"
+	"github.com/rfxxfy/LintVision/secrets"
+)
+
 type FileStats struct {
 	Path          string `json:"path,omitempty"`
 	Ext           string `json:"ext"`
 	Category      string `json:"category"`
+	Language      string `json:"language,omitempty"`
 	LinesTotal    int    `json:"lines_total"`
 	LinesCode     int    `json:"lines_code"`
 	LinesComments int    `json:"lines_comments"`
 	LinesBlank    int    `json:"lines_blank"`
+	Size          int64  `json:"size"`
 	Hash          string `json:"hash,omitempty"`
+	// SHA1 — SHA1 содержимого файла для SPDX (Hash — SHA256).
+	SHA1    string `json:"sha1,omitempty"`
+	License string `json:"license,omitempty"`
+	// LicenseMatch — лицензия, распознанная по тексту файла лицензии
+	// (LICENSE, COPYING и т. п.).
+	LicenseMatch *license.Match `json:"license_match,omitempty"`
+	Generated    bool           `json:"generated,omitempty"`
+	Vendored     bool           `json:"vendored,omitempty"`
+	Test         bool           `json:"test,omitempty"`
+
+	Markers []markers.Marker `json:"markers,omitempty"`
+	// Lint — находки правил стиля (см. пакет lint).
+	Lint []lint.Finding `json:"lint,omitempty"`
+	// Issues — замечания внешних линтеров из импортированных отчётов.
+	Issues []external.Finding `json:"issues,omitempty"`
+	// Coverage — покрытие строк тестами из файлов покрытия.
+	Coverage *Coverage `json:"coverage,omitempty"`
+	// History — история изменений файла по git.
+	History *FileHistory `json:"history,omitempty"`
+	// Authors — авторы строк файла по git blame.
+	Authors []history.AuthorLines `json:"authors,omitempty"`
+	// Owners — владельцы файла по CODEOWNERS.
+	Owners []string `json:"owners,omitempty"`
 }
 
 type ProjectStats struct {
+	// SchemaVersion — версия формата JSON-отчёта (см. SchemaVersion).
+	SchemaVersion int       `json:"schema_version"`
+	Metadata      *Metadata `json:"metadata,omitempty"`
+
+	// Root — анализируемая директория, Source — исходный URL репозитория (если есть).
+	Root   string `json:"root,omitempty"`
+	Source string `json:"source,omitempty"`
+
 	Files          []FileStats    `json:"files"`
 	CategoryCounts map[string]int `json:"category_counts"`
+	MarkerCounts   map[string]int `json:"marker_counts,omitempty"`
+	LintCounts     map[string]int `json:"lint_counts,omitempty"`
+
+	// IssueCounts, IssuesByLanguage и IssuesByDir — число замечаний
+	// внешних линтеров по инструментам, языкам и директориям верхнего
+	// уровня; UnmatchedIssues — замечания, не привязанные к файлам.
+	IssueCounts      map[string]int     `json:"issue_counts,omitempty"`
+	IssuesByLanguage map[string]int     `json:"issues_by_language,omitempty"`
+	IssuesByDir      map[string]int     `json:"issues_by_dir,omitempty"`
+	UnmatchedIssues  []external.Finding `json:"unmatched_issues,omitempty"`
+
+	// CoverageTotals, CoverageByLanguage и CoverageByDir — покрытие по
+	// файлам, для которых есть данные о покрытии.
+	CoverageTotals     *Coverage           `json:"coverage_totals,omitempty"`
+	CoverageByLanguage map[string]Coverage `json:"coverage_by_language,omitempty"`
+	CoverageByDir      map[string]Coverage `json:"coverage_by_dir,omitempty"`
+
+	// History — окно, по которому собрана история изменений файлов.
+	History *HistoryInfo `json:"history,omitempty"`
+	// Ownership — авторство строк по проекту и директориям.
+	Ownership *OwnershipReport `json:"ownership,omitempty"`
+	// CodeOwners — строки по владельцам из CODEOWNERS и файлы без владельцев.
+	CodeOwners *CodeOwnersReport `json:"codeowners,omitempty"`
+
+	Totals          LineTotals `json:"totals"`
+	GeneratedTotals LineTotals `json:"generated_totals"`
+	VendoredTotals  LineTotals `json:"vendored_totals"`
+	TestTotals      LineTotals `json:"test_totals"`
+	NonTestTotals   LineTotals `json:"non_test_totals"`
+
+	// TestRatioByLanguage и TestRatioByDir — соотношение тестового и
+	// нетестового кода по языкам и директориям верхнего уровня.
+	TestRatioByLanguage map[string]TestRatio `json:"test_ratio_by_language,omitempty"`
+	TestRatioByDir      map[string]TestRatio `json:"test_ratio_by_dir,omitempty"`
 
 	HiddenFiles   int `json:"hidden_files"`
 	HiddenDirs    int `json:"hidden_dirs"`
 	NonHiddenDirs int `json:"non_hidden_dirs"`
+
+	Dependencies []deps.Dependency `json:"dependencies,omitempty"`
+	Licenses     *license.Report   `json:"licenses,omitempty"`
+	Secrets      []secrets.Finding `json:"secrets,omitempty"`
+
+	// Violations — нарушения quality gates (см. пакет gates).
+	Violations []gates.Violation `json:"violations,omitempty"`
+}
+
+// RelPath возвращает путь файла относительно Root в slash-нотации.
+// Если Root не задан или путь лежит вне него, путь возвращается как есть.
+func (ps ProjectStats) RelPath(path string) string {
+	if ps.Root == "" {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(ps.Root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Name возвращает человекочитаемое имя проекта: URL источника
+// или имя корневой директории.
+func (ps ProjectStats) Name() string {
+	if ps.Source != "" {
+		return strings.TrimSuffix(strings.TrimRight(ps.Source, "/"), ".git")
+	}
+	if ps.Root != "" {
+		if abs, err := filepath.Abs(ps.Root); err == nil {
+			return filepath.Base(abs)
+		}
+		return filepath.Base(ps.Root)
+	}
+	return "project"
 }
@@ -0,0 +1,209 @@
+package stats
+
+import (
+	"sort"
+)
+
+// Статусы файлов в сравнении отчётов.
+const (
+	FileAdded    = "added"
+	FileRemoved  = "removed"
+	FileModified = "modified"
+	FileRenamed  = "renamed"
+)
+
+// FileChange — изменение одного файла между двумя отчётами.
+// Delta — новые значения минус старые (для удалённых — со знаком минус).
+type FileChange struct {
+	Status   string     `json:"status"`
+	Path     string     `json:"path"`
+	OldPath  string     `json:"old_path,omitempty"`
+	Language string     `json:"language,omitempty"`
+	Category string     `json:"category"`
+	Delta    LineTotals `json:"delta"`
+}
+
+// GroupDelta — итоги группы файлов (язык, категория, директория)
+// в старом и новом отчёте.
+type GroupDelta struct {
+	Name  string     `json:"name"`
+	Old   LineTotals `json:"old"`
+	New   LineTotals `json:"new"`
+	Delta LineTotals `json:"delta"`
+}
+
+// StatsDiff — результат сравнения двух отчётов.
+type StatsDiff struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+	Renamed  int `json:"renamed"`
+
+	Files       []FileChange `json:"files"`
+	Totals      GroupDelta   `json:"totals"`
+	Languages   []GroupDelta `json:"languages"`
+	Categories  []GroupDelta `json:"categories"`
+	Directories []GroupDelta `json:"directories"`
+}
+
+// DiffStats сравнивает два отчёта. Файлы сопоставляются по пути
+// относительно Root, а среди оставшихся без пары — по хешу содержимого,
+// что позволяет распознать переименования.
+func DiffStats(oldStats, newStats ProjectStats) StatsDiff {
+	d := StatsDiff{Old: oldStats.Name(), New: newStats.Name(), Files: []FileChange{}}
+
+	oldByPath := make(map[string]FileStats, len(oldStats.Files))
+	for _, f := range oldStats.Files {
+		oldByPath[oldStats.RelPath(f.Path)] = f
+	}
+
+	var added []FileStats
+	for _, f := range newStats.Files {
+		path := newStats.RelPath(f.Path)
+		before, ok := oldByPath[path]
+		if !ok {
+			added = append(added, f)
+			continue
+		}
+		delete(oldByPath, path)
+		if before.Hash == f.Hash && sameLines(before, f) {
+			continue
+		}
+		d.Files = append(d.Files, FileChange{
+			Status: FileModified, Path: path, Language: f.Language, Category: f.Category,
+			Delta: subTotals(fileTotals(f), fileTotals(before)),
+		})
+	}
+
+	removedByHash := make(map[string][]string)
+	for path, f := range oldByPath {
+		if f.Hash != "" {
+			removedByHash[f.Hash] = append(removedByHash[f.Hash], path)
+		}
+	}
+	for hash := range removedByHash {
+		sort.Strings(removedByHash[hash])
+	}
+	for _, f := range added {
+		path := newStats.RelPath(f.Path)
+		if candidates := removedByHash[f.Hash]; f.Hash != "" && len(candidates) > 0 {
+			oldPath := candidates[0]
+			removedByHash[f.Hash] = candidates[1:]
+			delete(oldByPath, oldPath)
+			d.Files = append(d.Files, FileChange{
+				Status: FileRenamed, Path: path, OldPath: oldPath, Language: f.Language, Category: f.Category,
+			})
+			continue
+		}
+		d.Files = append(d.Files, FileChange{
+			Status: FileAdded, Path: path, Language: f.Language, Category: f.Category,
+			Delta: fileTotals(f),
+		})
+	}
+	for path, f := range oldByPath {
+		d.Files = append(d.Files, FileChange{
+			Status: FileRemoved, Path: path, Language: f.Language, Category: f.Category,
+			Delta: subTotals(LineTotals{}, fileTotals(f)),
+		})
+	}
+	sort.Slice(d.Files, func(i, j int) bool { return d.Files[i].Path < d.Files[j].Path })
+
+	for _, c := range d.Files {
+		switch c.Status {
+		case FileAdded:
+			d.Added++
+		case FileRemoved:
+			d.Removed++
+		case FileModified:
+			d.Modified++
+		case FileRenamed:
+			d.Renamed++
+		}
+	}
+
+	d.Totals = GroupDelta{Name: "total", Old: oldStats.TotalsExcluding(false, false), New: newStats.TotalsExcluding(false, false)}
+	d.Totals.Delta = subTotals(d.Totals.New, d.Totals.Old)
+	d.Languages = groupDeltas(oldStats, newStats, func(ps ProjectStats, f FileStats) string { return f.Language })
+	d.Categories = groupDeltas(oldStats, newStats, func(ps ProjectStats, f FileStats) string { return f.Category })
+	d.Directories = groupDeltas(oldStats, newStats, func(ps ProjectStats, f FileStats) string { return ps.topDir(f.Path) })
+	return d
+}
+
+// Changed сообщает, отличаются ли отчёты хотя бы одним файлом.
+func (d StatsDiff) Changed() bool {
+	return len(d.Files) > 0
+}
+
+func sameLines(a, b FileStats) bool {
+	return a.LinesTotal == b.LinesTotal && a.LinesCode == b.LinesCode &&
+		a.LinesComments == b.LinesComments && a.LinesBlank == b.LinesBlank
+}
+
+func fileTotals(f FileStats) LineTotals {
+	var t LineTotals
+	t.add(f)
+	return t
+}
+
+func subTotals(a, b LineTotals) LineTotals {
+	return LineTotals{
+		Files:    a.Files - b.Files,
+		Lines:    a.Lines - b.Lines,
+		Code:     a.Code - b.Code,
+		Comments: a.Comments - b.Comments,
+		Blank:    a.Blank - b.Blank,
+	}
+}
+
+// groupDeltas группирует файлы обоих отчётов по ключу key и возвращает
+// группы, итоги которых изменились, по убыванию модуля изменения
+// строк кода.
+func groupDeltas(oldStats, newStats ProjectStats, key func(ProjectStats, FileStats) string) []GroupDelta {
+	groups := make(map[string]*GroupDelta)
+	collect := func(ps ProjectStats, isNew bool) {
+		for _, f := range ps.Files {
+			name := key(ps, f)
+			if name == "" {
+				continue
+			}
+			g, ok := groups[name]
+			if !ok {
+				g = &GroupDelta{Name: name}
+				groups[name] = g
+			}
+			if isNew {
+				g.New.add(f)
+			} else {
+				g.Old.add(f)
+			}
+		}
+	}
+	collect(oldStats, false)
+	collect(newStats, true)
+
+	result := []GroupDelta{}
+	for _, g := range groups {
+		g.Delta = subTotals(g.New, g.Old)
+		if g.Delta != (LineTotals{}) {
+			result = append(result, *g)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ai, aj := abs(result[i].Delta.Code), abs(result[j].Delta.Code)
+		if ai != aj {
+			return ai > aj
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -3,12 +3,96 @@ package stats
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/rfxxfy/LintVision/logging"
 )
 
+const (
+	FormatJSON      = "json"
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+	FormatCSV       = "csv"
+	FormatTSV       = "tsv"
+	FormatHTML      = "html"
+	FormatMarkdown  = "markdown"
+	FormatCloc      = "cloc"
+	FormatTokei     = "tokei"
+	FormatTable     = "table"
+	FormatJSONL     = "jsonl"
+	FormatSARIF     = "sarif"
+)
+
+var exporters = map[string]func(io.Writer, ProjectStats) error{
+	FormatJSON:      WriteJSON,
+	FormatCycloneDX: WriteCycloneDX,
+	FormatSPDX:      WriteSPDX,
+	FormatCSV:       WriteCSV,
+	FormatTSV:       WriteTSV,
+	FormatHTML:      WriteHTML,
+	FormatMarkdown:  WriteMarkdown,
+	FormatCloc:      WriteCloc,
+	FormatTokei:     WriteTokei,
+	FormatTable:     WriteTable,
+	FormatJSONL:     WriteJSONL,
+	FormatSARIF:     WriteSARIF,
+}
+
+// summaryExporters пишут дополнительную сводную таблицу в файл
+// CategorySummaryPath рядом с основным результатом.
+var summaryExporters = map[string]func(io.Writer, ProjectStats) error{
+	FormatCSV: WriteCategoryCSV,
+	FormatTSV: WriteCategoryTSV,
+}
+
+// formatSuffixes сопоставляет окончания имени файла форматам;
+// более длинные суффиксы проверяются первыми.
+var formatSuffixes = []struct {
+	suffix string
+	format string
+}{
+	{".cdx.json", FormatCycloneDX},
+	{".sarif.json", FormatSARIF},
+	{".sarif", FormatSARIF},
+	{".spdx", FormatSPDX},
+	{".json", FormatJSON},
+	{".jsonl", FormatJSONL},
+	{".ndjson", FormatJSONL},
+	{".csv", FormatCSV},
+	{".tsv", FormatTSV},
+	{".html", FormatHTML},
+	{".htm", FormatHTML},
+	{".md", FormatMarkdown},
+	{".markdown", FormatMarkdown},
+	{".txt", FormatTable},
+}
+
+// Formats возвращает отсортированный список поддерживаемых форматов вывода.
+func Formats() []string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatFromPath определяет формат вывода по расширению файла.
+func FormatFromPath(filePath string) (string, bool) {
+	name := strings.ToLower(filePath)
+	for _, fs := range formatSuffixes {
+		if strings.HasSuffix(name, fs.suffix) {
+			return fs.format, true
+		}
+	}
+	return "", false
+}
+
 func PrintStats(stats ProjectStats) {
+	stats.SchemaVersion = SchemaVersion
 	data, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
 		logging.Error("PrintStats: JSON marshal failed: %v", err)
@@ -17,31 +101,167 @@ func PrintStats(stats ProjectStats) {
 	fmt.Println(string(data))
 }
 
-func SaveStats(stats ProjectStats, filePath string) error {
+// printStats выводит результат в stdout в формате opts.Print.
+// printStats печатает stats в stdout в формате opts.Print; baseline
+// добавляет в Markdown изменения относительно прежнего отчёта.
+func printStats(stats ProjectStats, opts Options, baseline *ProjectStats) error {
+	switch opts.Print {
+	case "":
+		return nil
+	case FormatJSON:
+		PrintStats(stats)
+		return nil
+	case FormatTable:
+		return WriteTableReport(os.Stdout, stats, opts.Table)
+	case FormatMarkdown:
+		return WriteMarkdownReport(os.Stdout, stats, MarkdownOptions{Baseline: baseline})
+	default:
+		return WriteStats(os.Stdout, stats, opts.Print)
+	}
+}
+
+func WriteJSON(w io.Writer, stats ProjectStats) error {
+	stats.SchemaVersion = SchemaVersion
 	data, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
-		logging.Error("SaveStats: JSON marshal failed: %v", err)
 		return err
 	}
-	if err := os.WriteFile(filePath, data, 0o644); err != nil {
-		logging.Error("SaveStats: cannot write to %s: %v", filePath, err)
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteStats пишет stats в w в указанном формате (по умолчанию JSON).
+func WriteStats(w io.Writer, stats ProjectStats, format string) error {
+	if format == "" {
+		format = FormatJSON
+	}
+	export, ok := exporters[format]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	return export(w, stats)
+}
+
+// LoadStats читает ранее сохранённый JSON-отчёт любой поддерживаемой
+// версии схемы и приводит его к текущим структурам.
+func LoadStats(filePath string) (ProjectStats, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logging.Error("LoadStats: cannot read %s: %v", filePath, err)
+		return ProjectStats{}, err
+	}
+	stats, err := DecodeStats(data)
+	if err != nil {
+		logging.Error("LoadStats: invalid report %s: %v", filePath, err)
+		return stats, fmt.Errorf("invalid report %s: %w", filePath, err)
+	}
+	return stats, nil
+}
+
+func SaveStats(stats ProjectStats, filePath string) error {
+	return SaveStatsAs(stats, filePath, FormatJSON)
+}
+
+func SaveStatsAs(stats ProjectStats, filePath, format string) error {
+	if format == "" {
+		format = FormatJSON
+	}
+	export, ok := exporters[format]
+	if !ok {
+		logging.Error("SaveStatsAs: unknown format %q", format)
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	if err := saveWith(export, stats, filePath); err != nil {
+		logging.Error("SaveStatsAs: cannot write %s result to %s: %v", format, filePath, err)
 		return err
 	}
-	logging.Info("SaveStats: written result to %s", filePath)
+	if summary, ok := summaryExporters[format]; ok {
+		if err := saveWith(summary, stats, CategorySummaryPath(filePath)); err != nil {
+			logging.Error("SaveStatsAs: cannot write category summary: %v", err)
+			return err
+		}
+	}
+	logging.Info("SaveStatsAs: written %s result to %s", format, filePath)
 	return nil
 }
 
+func saveWith(export func(io.Writer, ProjectStats) error, stats ProjectStats, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := export(f, stats); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func AnalyzeAndSave(root, outPath string) (ProjectStats, error) {
-	stats, err := ComputeProjectStatsFromDir(root)
+	return AnalyzeAndSaveAs(root, outPath, Options{Format: FormatJSON, Print: FormatJSON})
+}
+
+func AnalyzeAndSaveAs(root, outPath string, opts Options) (ProjectStats, error) {
+	if (outPath != "" && opts.Format == FormatJSONL) || (outPath == "" && opts.Print == FormatJSONL) {
+		return streamAndSave(root, outPath, opts)
+	}
+	stats, err := Analyze(root, opts)
 	if err != nil {
-		logging.Error("AnalyzeAndSave: ComputeProjectStatsFromDir failed: %v", err)
+		logging.Error("AnalyzeAndSave: Analyze failed: %v", err)
+		return stats, err
+	}
+	var baseline *ProjectStats
+	if opts.Compare != "" && (opts.Print == FormatMarkdown || (outPath != "" && opts.Format == FormatMarkdown)) {
+		prev, err := LoadStats(opts.Compare)
+		if err != nil {
+			logging.Error("AnalyzeAndSave: cannot load %s: %v", opts.Compare, err)
+			return stats, err
+		}
+		baseline = &prev
+	}
+	if err := printStats(stats, opts, baseline); err != nil {
+		logging.Error("AnalyzeAndSave: cannot print result: %v", err)
 		return stats, err
 	}
-	PrintStats(stats)
-	if outPath != "" {
-		if err := SaveStats(stats, outPath); err != nil {
+	if outPath == "" {
+		return stats, nil
+	}
+	if opts.Format == FormatMarkdown && baseline != nil {
+		report := func(w io.Writer, ps ProjectStats) error {
+			return WriteMarkdownReport(w, ps, MarkdownOptions{Baseline: baseline})
+		}
+		if err := saveWith(report, stats, outPath); err != nil {
+			logging.Error("AnalyzeAndSave: cannot write markdown to %s: %v", outPath, err)
 			return stats, err
 		}
+		return stats, nil
+	}
+	if err := SaveStatsAs(stats, outPath, opts.Format); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// streamAndSave пишет JSON Lines по мере анализа в outPath или, если он
+// пуст, в stdout. Другой вывод в stdout в этом режиме не печатается.
+func streamAndSave(root, outPath string, opts Options) (ProjectStats, error) {
+	if outPath == "" {
+		return StreamJSONL(root, os.Stdout, opts)
+	}
+	f, err := os.Create(outPath)
+	if err != nil {
+		logging.Error("AnalyzeAndSave: cannot create %s: %v", outPath, err)
+		return ProjectStats{}, err
+	}
+	stats, err := StreamJSONL(root, f, opts)
+	if err != nil {
+		f.Close()
+		logging.Error("AnalyzeAndSave: streaming to %s failed: %v", outPath, err)
+		return stats, err
+	}
+	if err := f.Close(); err != nil {
+		return stats, err
 	}
+	logging.Info("AnalyzeAndSave: streamed jsonl result to %s", outPath)
 	return stats, nil
 }
@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rfxxfy/LintVision/history"
+)
+
+// csvFileHeader — колонки таблицы по файлам. Списки (маркеры, находки
+// правил стиля, замечания линтеров) выводятся числом элементов, владельцы
+// из CODEOWNERS — через пробел; пустое покрытие — файл без данных о нём.
+// Колонки истории и авторства пусты, если -history или -blame не
+// включались; top_author — email автора с наибольшим числом строк.
+var csvFileHeader = []string{
+	"path", "ext", "category", "language",
+	"lines_total", "lines_code", "lines_comments", "lines_blank", "size",
+	"hash", "license", "generated", "vendored", "test", "markers",
+	"lint", "issues", "coverage_percent", "owners",
+	"commits", "lines_added", "lines_removed", "last_modified", "hotspot",
+	"authors", "top_author", "bus_factor",
+}
+
+var csvCategoryHeader = []string{
+	"category", "files", "lines_total", "lines_code", "lines_comments", "lines_blank",
+}
+
+func WriteCSV(w io.Writer, stats ProjectStats) error {
+	return writeFilesTable(w, stats, ',')
+}
+
+func WriteTSV(w io.Writer, stats ProjectStats) error {
+	return writeFilesTable(w, stats, '\t')
+}
+
+func WriteCategoryCSV(w io.Writer, stats ProjectStats) error {
+	return writeCategoryTable(w, stats, ',')
+}
+
+func WriteCategoryTSV(w io.Writer, stats ProjectStats) error {
+	return writeCategoryTable(w, stats, '\t')
+}
+
+// writeFilesTable пишет по строке на каждый FileStats.
+func writeFilesTable(w io.Writer, stats ProjectStats, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(csvFileHeader); err != nil {
+		return err
+	}
+	for _, f := range stats.Files {
+		record := []string{
+			stats.RelPath(f.Path), f.Ext, f.Category, f.Language,
+			strconv.Itoa(f.LinesTotal), strconv.Itoa(f.LinesCode),
+			strconv.Itoa(f.LinesComments), strconv.Itoa(f.LinesBlank),
+			strconv.FormatInt(f.Size, 10),
+			f.Hash, f.License,
+			strconv.FormatBool(f.Generated), strconv.FormatBool(f.Vendored), strconv.FormatBool(f.Test),
+			strconv.Itoa(len(f.Markers)), strconv.Itoa(len(f.Lint)), strconv.Itoa(len(f.Issues)),
+			csvCoverage(f.Coverage), strings.Join(f.Owners, " "),
+		}
+		record = append(record, csvHistory(f.History)...)
+		record = append(record, csvAuthors(f.Authors)...)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCoverage(c *Coverage) string {
+	if c == nil {
+		return ""
+	}
+	return strconv.FormatFloat(c.Percent, 'f', 1, 64)
+}
+
+func csvHistory(h *FileHistory) []string {
+	if h == nil {
+		return []string{"", "", "", "", ""}
+	}
+	return []string{
+		strconv.Itoa(h.Commits), strconv.Itoa(h.LinesAdded), strconv.Itoa(h.LinesRemoved),
+		h.LastModified.UTC().Format(time.RFC3339), strconv.FormatFloat(h.Hotspot, 'f', -1, 64),
+	}
+}
+
+func csvAuthors(authors []history.AuthorLines) []string {
+	if len(authors) == 0 {
+		return []string{"", "", ""}
+	}
+	byEmail := make(map[string]history.AuthorLines, len(authors))
+	for _, a := range authors {
+		addAuthorLines(byEmail, a)
+	}
+	o := newOwnership(byEmail)
+	return []string{strconv.Itoa(len(o.Authors)), o.Authors[0].Email, strconv.Itoa(o.BusFactor)}
+}
+
+// writeCategoryTable пишет сводку по категориям файлов.
+func writeCategoryTable(w io.Writer, stats ProjectStats, comma rune) error {
+	totals := make(map[string]*LineTotals)
+	for _, f := range stats.Files {
+		t, ok := totals[f.Category]
+		if !ok {
+			t = &LineTotals{}
+			totals[f.Category] = t
+		}
+		t.add(f)
+	}
+	categories := make([]string, 0, len(totals))
+	for c := range totals {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(csvCategoryHeader); err != nil {
+		return err
+	}
+	for _, c := range categories {
+		t := totals[c]
+		record := []string{
+			c, strconv.Itoa(t.Files), strconv.Itoa(t.Lines),
+			strconv.Itoa(t.Code), strconv.Itoa(t.Comments), strconv.Itoa(t.Blank),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CategorySummaryPath возвращает путь файла со сводкой по категориям,
+// который сохраняется рядом с табличным отчётом: out.csv -> out.categories.csv.
+func CategorySummaryPath(filePath string) string {
+	ext := filepath.Ext(filePath)
+	return strings.TrimSuffix(filePath, ext) + ".categories" + ext
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// FormatText — текстовый вывод сравнения отчётов для терминала.
+const FormatText = "text"
+
+var diffExporters = map[string]func(io.Writer, StatsDiff) error{
+	FormatText:     WriteDiffText,
+	FormatJSON:     WriteDiffJSON,
+	FormatMarkdown: WriteDiffMarkdown,
+}
+
+// DiffFormats возвращает отсортированный список форматов вывода сравнения.
+func DiffFormats() []string {
+	names := make([]string, 0, len(diffExporters))
+	for name := range diffExporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteDiff пишет сравнение в указанном формате (по умолчанию текст).
+func WriteDiff(w io.Writer, d StatsDiff, format string) error {
+	if format == "" {
+		format = FormatText
+	}
+	export, ok := diffExporters[format]
+	if !ok {
+		return fmt.Errorf("unknown diff format %q", format)
+	}
+	return export(w, d)
+}
+
+func WriteDiffJSON(w io.Writer, d StatsDiff) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteDiffText пишет сравнение в виде выровненных таблиц.
+func WriteDiffText(w io.Writer, d StatsDiff) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s -> %s\n", d.Old, d.New)
+	fmt.Fprintf(bw, "Файлы: +%d добавлено, -%d удалено, ~%d изменено, %d переименовано\n\n",
+		d.Added, d.Removed, d.Modified, d.Renamed)
+
+	header := []string{"", "Файлы", "Строки", "Код", "Комментарии", "Пустые"}
+	renderTable(bw, header, [][]string{deltaRow("Итого", d.Totals.Delta)}, nil, 1, false)
+
+	for _, section := range []struct {
+		title  string
+		groups []GroupDelta
+	}{
+		{"Язык", d.Languages},
+		{"Категория", d.Categories},
+		{"Директория", d.Directories},
+	} {
+		if len(section.groups) == 0 {
+			continue
+		}
+		rows := make([][]string, 0, len(section.groups))
+		for _, g := range section.groups {
+			rows = append(rows, deltaRow(g.Name, g.Delta))
+		}
+		header[0] = section.title
+		fmt.Fprintln(bw)
+		renderTable(bw, header, rows, nil, 1, false)
+	}
+
+	if len(d.Files) > 0 {
+		fmt.Fprintln(bw)
+		rows := make([][]string, 0, len(d.Files))
+		for _, c := range d.Files {
+			rows = append(rows, []string{diffStatusMark(c.Status), describeChange(c), signed(c.Delta.Lines), signed(c.Delta.Code)})
+		}
+		renderTable(bw, []string{"", "Файл", "Строки", "Код"}, rows, nil, 2, false)
+	}
+	return bw.Flush()
+}
+
+// WriteDiffMarkdown пишет сравнение для комментария к pull request.
+func WriteDiffMarkdown(w io.Writer, d StatsDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## LintVision: %s → %s\n\n", escapeMarkdown(d.Old), escapeMarkdown(d.New))
+	fmt.Fprintf(&b, "Добавлено: **%d**, удалено: **%d**, изменено: **%d**, переименовано: **%d**\n\n",
+		d.Added, d.Removed, d.Modified, d.Renamed)
+
+	b.WriteString("| | Файлов | Строк | Код | Комментарии | Пустые |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+	writeMarkdownGroup(&b, "**Итого**", d.Totals)
+	b.WriteString("\n")
+
+	for _, section := range []struct {
+		title  string
+		groups []GroupDelta
+	}{
+		{"Языки", d.Languages},
+		{"Категории", d.Categories},
+		{"Директории", d.Directories},
+	} {
+		if len(section.groups) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n\n", section.title)
+		b.WriteString("| | Файлов | Строк | Код | Комментарии | Пустые |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+		for _, g := range section.groups {
+			writeMarkdownGroup(&b, escapeMarkdown(g.Name), g)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.Files) > 0 {
+		b.WriteString("<details><summary>Изменённые файлы</summary>\n\n")
+		b.WriteString("| | Файл | Строк | Код |\n")
+		b.WriteString("|---|---|---:|---:|\n")
+		for _, c := range d.Files {
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", diffStatusMark(c.Status),
+				strings.ReplaceAll(describeChange(c), "`", "'"), signed(c.Delta.Lines), signed(c.Delta.Code))
+		}
+		b.WriteString("\n</details>\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownGroup(b *strings.Builder, name string, g GroupDelta) {
+	fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s |\n", name,
+		formatDelta(g.New.Files, g.Old.Files), formatDelta(g.New.Lines, g.Old.Lines),
+		formatDelta(g.New.Code, g.Old.Code), formatDelta(g.New.Comments, g.Old.Comments),
+		formatDelta(g.New.Blank, g.Old.Blank))
+}
+
+func deltaRow(name string, t LineTotals) []string {
+	return []string{name, signed(t.Files), signed(t.Lines), signed(t.Code), signed(t.Comments), signed(t.Blank)}
+}
+
+// signed выводит число со знаком: "+5", "-3", "0".
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func diffStatusMark(status string) string {
+	switch status {
+	case FileAdded:
+		return "A"
+	case FileRemoved:
+		return "D"
+	case FileRenamed:
+		return "R"
+	default:
+		return "M"
+	}
+}
+
+func describeChange(c FileChange) string {
+	if c.Status == FileRenamed {
+		return c.OldPath + " -> " + c.Path
+	}
+	return c.Path
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io"
+	"time"
+)
+
+//go:embed templates/report.html
+var templatesFS embed.FS
+
+var htmlReportTemplate = template.Must(template.ParseFS(templatesFS, "templates/report.html"))
+
+type htmlReport struct {
+	Title     string
+	Generated string
+	Totals    LineTotals
+	Data      template.JS
+}
+
+type htmlNamedCount struct {
+	Name  string `json:"name"`
+	Code  int    `json:"code,omitempty"`
+	Files int    `json:"files,omitempty"`
+}
+
+type htmlFile struct {
+	Path      string `json:"path"`
+	Language  string `json:"language"`
+	Category  string `json:"category"`
+	Total     int    `json:"total"`
+	Code      int    `json:"code"`
+	Comments  int    `json:"comments"`
+	Blank     int    `json:"blank"`
+	Generated bool   `json:"generated,omitempty"`
+	Vendored  bool   `json:"vendored,omitempty"`
+	Test      bool   `json:"test,omitempty"`
+}
+
+type htmlData struct {
+	Title      string           `json:"title"`
+	Languages  []htmlNamedCount `json:"languages"`
+	Categories []htmlNamedCount `json:"categories"`
+	Files      []htmlFile       `json:"files"`
+}
+
+// WriteHTML пишет самодостаточный HTML-отчёт: все стили, скрипты и данные
+// встроены в документ, внешние ресурсы не используются.
+func WriteHTML(w io.Writer, stats ProjectStats) error {
+	data := htmlData{
+		Title:      stats.Name(),
+		Languages:  []htmlNamedCount{},
+		Categories: []htmlNamedCount{},
+		Files:      make([]htmlFile, 0, len(stats.Files)),
+	}
+
+	summary := Summarize(stats, 0)
+	for _, l := range summary.Languages {
+		data.Languages = append(data.Languages, htmlNamedCount{Name: l.Name, Code: l.Code})
+	}
+	for _, c := range summary.Categories {
+		data.Categories = append(data.Categories, htmlNamedCount{Name: c.Name, Files: c.Files})
+	}
+	for _, f := range stats.Files {
+		data.Files = append(data.Files, htmlFile{
+			Path:      stats.RelPath(f.Path),
+			Language:  f.Language,
+			Category:  f.Category,
+			Total:     f.LinesTotal,
+			Code:      f.LinesCode,
+			Comments:  f.LinesComments,
+			Blank:     f.LinesBlank,
+			Generated: f.Generated,
+			Vendored:  f.Vendored,
+			Test:      f.Test,
+		})
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return htmlReportTemplate.Execute(w, htmlReport{
+		Title:     data.Title,
+		Generated: time.Now().Format("2006-01-02 15:04:05"),
+		Totals:    stats.Totals,
+		Data:      template.JS(payload),
+	})
+}
@@ -0,0 +1,155 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMarkdownTopN — число крупнейших файлов в Markdown-отчёте.
+	DefaultMarkdownTopN = 10
+	// DefaultMarkdownMaxBytes держит отчёт в пределах лимита комментария
+	// GitHub (65536 символов) с запасом.
+	DefaultMarkdownMaxBytes = 60000
+)
+
+type MarkdownOptions struct {
+	TopN     int
+	MaxBytes int
+	// Baseline — предыдущий отчёт; если задан, в таблицы добавляются изменения.
+	Baseline *ProjectStats
+}
+
+func WriteMarkdown(w io.Writer, stats ProjectStats) error {
+	return WriteMarkdownReport(w, stats, MarkdownOptions{})
+}
+
+// WriteMarkdownReport пишет сводку для комментария к pull request.
+// Если отчёт не укладывается в MaxBytes, сокращаются списки файлов и языков.
+func WriteMarkdownReport(w io.Writer, stats ProjectStats, opts MarkdownOptions) error {
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultMarkdownTopN
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMarkdownMaxBytes
+	}
+
+	topN, maxLanguages := opts.TopN, -1
+	out := renderMarkdown(stats, opts.Baseline, topN, maxLanguages)
+	for len(out) > opts.MaxBytes && (topN > 0 || maxLanguages != 0) {
+		switch {
+		case topN > 0:
+			topN /= 2
+		case maxLanguages < 0:
+			maxLanguages = 20
+		default:
+			maxLanguages /= 2
+		}
+		out = renderMarkdown(stats, opts.Baseline, topN, maxLanguages)
+	}
+	if len(out) > opts.MaxBytes {
+		out = truncateMarkdown(out, opts.MaxBytes)
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// truncateMarkdown обрезает out до maxBytes по последнему переводу строки,
+// чтобы не оборвать строку таблицы, а если его нет — по границе символа
+// UTF-8.
+func truncateMarkdown(out string, maxBytes int) string {
+	if i := strings.LastIndexByte(out[:maxBytes], '\n'); i >= 0 {
+		return out[:i+1]
+	}
+	for maxBytes > 0 && !utf8.RuneStart(out[maxBytes]) {
+		maxBytes--
+	}
+	return out[:maxBytes]
+}
+
+func renderMarkdown(stats ProjectStats, baseline *ProjectStats, topN, maxLanguages int) string {
+	cur := Summarize(stats, topN)
+	var old Summary
+	oldLanguages := make(map[string]LineTotals)
+	if baseline != nil {
+		old = Summarize(*baseline, 0)
+		for _, l := range old.Languages {
+			oldLanguages[l.Name] = l.LineTotals
+		}
+	}
+	delta := func(now, before int) string {
+		if baseline == nil {
+			return fmt.Sprintf("%d", now)
+		}
+		return formatDelta(now, before)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## LintVision: %s\n\n", escapeMarkdown(stats.Name()))
+
+	b.WriteString("| Файлов | Строк | Код | Комментарии | Пустые |\n")
+	b.WriteString("|---:|---:|---:|---:|---:|\n")
+	fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n\n",
+		delta(cur.Totals.Files, old.Totals.Files), delta(cur.Totals.Lines, old.Totals.Lines),
+		delta(cur.Totals.Code, old.Totals.Code), delta(cur.Totals.Comments, old.Totals.Comments),
+		delta(cur.Totals.Blank, old.Totals.Blank))
+
+	languages := cur.Languages
+	if baseline != nil {
+		seen := make(map[string]bool, len(languages))
+		for _, l := range languages {
+			seen[l.Name] = true
+		}
+		for _, l := range old.Languages {
+			if !seen[l.Name] {
+				languages = append(languages, LanguageRow{Name: l.Name})
+			}
+		}
+	}
+	if len(languages) > 0 {
+		b.WriteString("### Языки\n\n")
+		b.WriteString("| Язык | Файлов | Код | Комментарии | Пустые |\n")
+		b.WriteString("|---|---:|---:|---:|---:|\n")
+		for i, l := range languages {
+			if maxLanguages >= 0 && i >= maxLanguages {
+				fmt.Fprintf(&b, "| …и ещё %d | | | | |\n", len(languages)-maxLanguages)
+				break
+			}
+			o := oldLanguages[l.Name]
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", escapeMarkdown(l.Name),
+				delta(l.Files, o.Files), delta(l.Code, o.Code),
+				delta(l.Comments, o.Comments), delta(l.Blank, o.Blank))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(cur.Largest) > 0 {
+		fmt.Fprintf(&b, "### Крупнейшие файлы (топ-%d)\n\n", len(cur.Largest))
+		b.WriteString("| Файл | Строк | Код |\n")
+		b.WriteString("|---|---:|---:|\n")
+		for _, f := range cur.Largest {
+			fmt.Fprintf(&b, "| `%s` | %d | %d |\n", strings.ReplaceAll(stats.RelPath(f.Path), "`", "'"),
+				f.LinesTotal, f.LinesCode)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatDelta выводит значение с изменением относительно базового: "120 (▲ 20)".
+func formatDelta(now, before int) string {
+	switch d := now - before; {
+	case d > 0:
+		return fmt.Sprintf("%d (▲ %d)", now, d)
+	case d < 0:
+		return fmt.Sprintf("%d (▼ %d)", now, -d)
+	default:
+		return fmt.Sprintf("%d", now)
+	}
+}
+
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`).Replace(s)
+}
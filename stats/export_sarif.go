@@ -0,0 +1,318 @@
+package stats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/lint"
+	"github.com/rfxxfy/LintVision/logging"
+	"github.com/rfxxfy/LintVision/secrets"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+	sarifToolURI = "https://github.com/rfxxfy/LintVision"
+	// sarifRootID — uriBaseId анализируемой директории; пути в
+	// результатах указываются относительно неё.
+	sarifRootID = "SRCROOT"
+	// sarifFingerprint — ключ отпечатка результата в fingerprints.
+	sarifFingerprint = "lintvision/v1"
+)
+
+// Префиксы ID правил SARIF для находок, не относящихся к правилам стиля;
+// правила стиля используют свои ID без префикса, как в директивах
+// lintvision:ignore, а замечания внешних линтеров — префикс с именем
+// инструмента.
+const (
+	sarifSecretsPrefix = "secrets/"
+	sarifLicensePrefix = "license/"
+	sarifGatesPrefix   = "gates/"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	ColumnKind         string                      `json:"columnKind"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string            `json:"ruleId"`
+	RuleIndex    int               `json:"ruleIndex"`
+	Level        string            `json:"level"`
+	Message      sarifMessage      `json:"message"`
+	Locations    []sarifLocation   `json:"locations,omitempty"`
+	Fingerprints map[string]string `json:"fingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// gateDescriptions — описания правил quality gates для SARIF.
+var gateDescriptions = map[string]string{
+	gates.RuleMaxLinesPerFile:       "Файл длиннее допустимого числа строк",
+	gates.RuleMaxFileBytes:          "Файл больше допустимого размера",
+	gates.RuleMinCommentRatio:       "Доля комментариев ниже порога",
+	gates.RuleMaxDuplicationPercent: "Доля дублированного кода выше порога",
+	gates.RuleMaxUnknownFiles:       "Слишком много файлов неизвестного типа",
+}
+
+// WriteSARIF пишет находки отчёта в формате SARIF 2.1.0: правила стиля,
+// замечания внешних линтеров, секреты, несоответствия лицензий и нарушения quality gates. Пути
+// указываются относительно анализируемой директории, отпечатки
+// результатов строятся по содержимому строки находки. Нарушения порогов
+// уровня проекта не привязаны к файлу и в SARIF не попадают: они есть
+// в JSON-отчёте и в stderr вместе с кодом выхода.
+func WriteSARIF(w io.Writer, stats ProjectStats) error {
+	b := sarifBuilder{
+		stats:     stats,
+		lines:     make(map[string][]string),
+		seen:      make(map[string]int),
+		ruleIndex: make(map[string]int),
+		run: sarifRun{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name: sbomToolName, Version: ToolVersion, InformationURI: sarifToolURI,
+				Rules: []sarifRule{},
+			}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    []sarifResult{},
+		},
+	}
+	if uri, ok := sarifRootURI(stats.Root); ok {
+		b.run.OriginalURIBaseIDs = map[string]sarifArtifactLoc{sarifRootID: {URI: uri}}
+	}
+
+	for _, f := range stats.Files {
+		for _, l := range f.Lint {
+			b.add(l.Rule, lint.Description(l.Rule), sarifLevel(l.Severity), l.Path, l.Line, l.Column, l.Message)
+		}
+	}
+	for _, f := range stats.Files {
+		for _, i := range f.Issues {
+			id := i.Tool
+			if i.Rule != "" {
+				id += "/" + i.Rule
+			}
+			b.add(id, "", sarifLevel(i.Severity), i.Path, i.Line, i.Column, i.Message)
+		}
+	}
+	for _, s := range stats.Secrets {
+		b.add(sarifSecretsPrefix+s.RuleID, secretDescription(s.RuleID), "error", s.Path, s.Line, 0,
+			fmt.Sprintf("вероятный секрет (%s)", s.RuleID))
+	}
+	if lr := stats.Licenses; lr != nil {
+		for _, c := range lr.Conflicts {
+			b.add(sarifLicensePrefix+"conflict", "Лицензия файла не соответствует лицензии проекта", "warning",
+				c.Path, 0, 0, fmt.Sprintf("лицензия %s, у проекта %s", c.License, c.Expected))
+		}
+		if lr.Project != "" {
+			for _, p := range lr.MissingHeader {
+				b.add(sarifLicensePrefix+"missing-header", "Нет SPDX-заголовка лицензии", "note",
+					p, 0, 0, "нет тега SPDX-License-Identifier")
+			}
+		}
+	}
+	for _, v := range stats.Violations {
+		if v.Path == "" {
+			continue
+		}
+		b.add(sarifGatesPrefix+v.Rule, gateDescriptions[v.Rule], "error", v.Path, 0, 0, v.Message)
+	}
+
+	log := sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{b.run}}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+type sarifBuilder struct {
+	stats ProjectStats
+	// lines — строки файлов по путям относительно корня; nil, если
+	// файл не удалось прочитать.
+	lines map[string][]string
+	// seen — число уже добавленных результатов с тем же ключом отпечатка.
+	seen      map[string]int
+	ruleIndex map[string]int
+	run       sarifRun
+}
+
+// add добавляет результат и, при первом упоминании, описание правила.
+func (b *sarifBuilder) add(ruleID, description, level, path string, line, column int, message string) {
+	driver := &b.run.Tool.Driver
+	index, ok := b.ruleIndex[ruleID]
+	if !ok {
+		if description == "" {
+			description = ruleID
+		}
+		index = len(driver.Rules)
+		b.ruleIndex[ruleID] = index
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID: ruleID, ShortDescription: sarifMessage{Text: description},
+			DefaultConfiguration: sarifConfiguration{Level: level},
+		})
+	}
+
+	r := sarifResult{RuleID: ruleID, RuleIndex: index, Level: level, Message: sarifMessage{Text: message}}
+	if path != "" {
+		rel := b.stats.RelPath(path)
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLoc{URI: sarifRelURI(rel)}}
+		if b.run.OriginalURIBaseIDs != nil {
+			loc.ArtifactLocation.URIBaseID = sarifRootID
+		}
+		if line > 0 {
+			loc.Region = &sarifRegion{StartLine: line, StartColumn: column}
+		}
+		r.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		if fingerprint, ok := b.fingerprint(path, rel, ruleID, line); ok {
+			r.Fingerprints = map[string]string{sarifFingerprint: fingerprint}
+		}
+	}
+	b.run.Results = append(b.run.Results, r)
+}
+
+// fingerprint строит отпечаток результата по правилу, пути и строке
+// находки с нормализованными пробелами. Номер строки и колонка в него
+// не входят, поэтому отпечаток не меняется, когда находку сдвигают
+// правки выше по файлу; одинаковые находки различаются порядковым
+// номером. false — строку файла не удалось прочитать.
+func (b *sarifBuilder) fingerprint(path, rel, ruleID string, line int) (string, bool) {
+	content := ""
+	if line > 0 {
+		lines, ok := b.lines[rel]
+		if !ok {
+			lines = b.readLines(path, rel)
+			b.lines[rel] = lines
+		}
+		if line > len(lines) {
+			return "", false
+		}
+		content = strings.Join(strings.Fields(lines[line-1]), " ")
+	}
+	contentSum := sha256.Sum256([]byte(content))
+	key := ruleID + ":" + rel + ":" + hex.EncodeToString(contentSum[:])
+	n := b.seen[key]
+	b.seen[key]++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", key, n)))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// readLines читает строки файла находки; относительные пути
+// отсчитываются от корня отчёта.
+func (b *sarifBuilder) readLines(path, rel string) []string {
+	if !filepath.IsAbs(path) && b.stats.Root != "" {
+		path = filepath.Join(b.stats.Root, filepath.FromSlash(rel))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logging.Warn("WriteSARIF: no fingerprints for %s: %v", rel, err)
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+}
+
+// sarifLevel сопоставляет уровень серьёзности правил стиля уровню SARIF.
+func sarifLevel(severity string) string {
+	switch severity {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityInfo:
+		return "note"
+	}
+	return "warning"
+}
+
+func secretDescription(id string) string {
+	for _, r := range secrets.Rules {
+		if r.ID == id {
+			return r.Description
+		}
+	}
+	switch id {
+	case secrets.RuleHighEntropy:
+		return "Строка с высокой энтропией"
+	case secrets.RuleCredentialFile:
+		return "Файл с учётными данными"
+	}
+	return ""
+}
+
+// sarifRootURI возвращает file:// URI корня с завершающим слешем,
+// как того требует SARIF для originalUriBaseIds.
+func sarifRootURI(root string) (string, bool) {
+	if root == "" {
+		return "", false
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // C:/… в Windows
+	}
+	if !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String(), true
+}
+
+// sarifRelURI экранирует относительный путь как URI-ссылку.
+func sarifRelURI(rel string) string {
+	return (&url.URL{Path: rel}).String()
+}
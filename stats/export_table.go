@@ -0,0 +1,190 @@
+package stats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Поля сортировки таблицы файлов.
+const (
+	SortByLines    = "lines"
+	SortByCode     = "code"
+	SortByComments = "comments"
+	SortByBlank    = "blank"
+	SortByPath     = "path"
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiReset = "\x1b[0m"
+)
+
+// TableOptions настраивает текстовую таблицу для терминала.
+type TableOptions struct {
+	// Files добавляет таблицу по отдельным файлам.
+	Files bool
+	// SortBy — поле сортировки таблицы файлов (по умолчанию SortByLines).
+	SortBy string
+	// TopN ограничивает число строк таблицы файлов (0 — без ограничения).
+	TopN int
+	// Color включает ANSI-цвета.
+	Color bool
+}
+
+// SortFields возвращает допустимые значения TableOptions.SortBy.
+func SortFields() []string {
+	return []string{SortByLines, SortByCode, SortByComments, SortByBlank, SortByPath}
+}
+
+// WriteTable пишет сводную таблицу по языкам без цвета.
+func WriteTable(w io.Writer, stats ProjectStats) error {
+	return WriteTableReport(w, stats, TableOptions{})
+}
+
+// WriteTableReport пишет выровненную таблицу по языкам со строкой
+// итогов и, если включено, таблицу по файлам.
+func WriteTableReport(w io.Writer, stats ProjectStats, opts TableOptions) error {
+	bw := bufio.NewWriter(w)
+	summary := Summarize(stats, 0)
+
+	header := []string{"Язык", "Файлы", "Строки", "Код", "Комментарии", "Пустые"}
+	var rows [][]string
+	other := summary.Totals
+	for _, l := range summary.Languages {
+		rows = append(rows, lineTotalsRow(l.Name, l.LineTotals))
+		other.Files -= l.Files
+		other.Lines -= l.Lines
+		other.Code -= l.Code
+		other.Comments -= l.Comments
+		other.Blank -= l.Blank
+	}
+	if other.Files > 0 {
+		rows = append(rows, lineTotalsRow("Прочие", other))
+	}
+	footer := lineTotalsRow("Итого", summary.Totals)
+	renderTable(bw, header, rows, footer, 1, opts.Color)
+
+	if opts.Files {
+		files, err := sortedFiles(stats.Files, opts.SortBy)
+		if err != nil {
+			return err
+		}
+		if opts.TopN > 0 && len(files) > opts.TopN {
+			files = files[:opts.TopN]
+		}
+		header := []string{"Файл", "Язык", "Строки", "Код", "Комментарии", "Пустые"}
+		rows := make([][]string, 0, len(files))
+		for _, f := range files {
+			lang := f.Language
+			if lang == "" {
+				lang = "-"
+			}
+			rows = append(rows, []string{
+				stats.RelPath(f.Path), lang,
+				strconv.Itoa(f.LinesTotal), strconv.Itoa(f.LinesCode),
+				strconv.Itoa(f.LinesComments), strconv.Itoa(f.LinesBlank),
+			})
+		}
+		fmt.Fprintln(bw)
+		renderTable(bw, header, rows, nil, 2, opts.Color)
+	}
+	return bw.Flush()
+}
+
+func lineTotalsRow(name string, t LineTotals) []string {
+	return []string{
+		name, strconv.Itoa(t.Files), strconv.Itoa(t.Lines),
+		strconv.Itoa(t.Code), strconv.Itoa(t.Comments), strconv.Itoa(t.Blank),
+	}
+}
+
+// sortedFiles возвращает копию files, упорядоченную по полю sortBy
+// (числовые поля — по убыванию, путь — по возрастанию).
+func sortedFiles(files []FileStats, sortBy string) ([]FileStats, error) {
+	var key func(FileStats) int
+	switch sortBy {
+	case "", SortByLines:
+		key = func(f FileStats) int { return f.LinesTotal }
+	case SortByCode:
+		key = func(f FileStats) int { return f.LinesCode }
+	case SortByComments:
+		key = func(f FileStats) int { return f.LinesComments }
+	case SortByBlank:
+		key = func(f FileStats) int { return f.LinesBlank }
+	case SortByPath:
+	default:
+		return nil, fmt.Errorf("unknown sort field %q", sortBy)
+	}
+
+	sorted := append([]FileStats(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if key != nil && key(sorted[i]) != key(sorted[j]) {
+			return key(sorted[i]) > key(sorted[j])
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted, nil
+}
+
+// renderTable выравнивает колонки: первые textCols — по левому краю,
+// остальные (числовые) — по правому. Цвет применяется к строке целиком уже после
+// выравнивания, чтобы escape-последовательности не влияли на ширину.
+func renderTable(w io.Writer, header []string, rows [][]string, footer []string, textCols int, color bool) {
+	widths := make([]int, len(header))
+	all := append([][]string{header}, rows...)
+	if footer != nil {
+		all = append(all, footer)
+	}
+	for _, row := range all {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	total := 2 * (len(widths) - 1)
+	for _, width := range widths {
+		total += width
+	}
+
+	line := func(row []string, style string) {
+		var sb strings.Builder
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			if i < textCols {
+				sb.WriteString(cell + pad)
+			} else {
+				sb.WriteString(pad + cell)
+			}
+		}
+		text := strings.TrimRight(sb.String(), " ")
+		if color && style != "" {
+			text = style + text + ansiReset
+		}
+		fmt.Fprintln(w, text)
+	}
+	separator := func() {
+		text := strings.Repeat("-", total)
+		if color {
+			text = ansiDim + text + ansiReset
+		}
+		fmt.Fprintln(w, text)
+	}
+
+	line(header, ansiBold)
+	separator()
+	for _, row := range rows {
+		line(row, "")
+	}
+	if footer != nil {
+		separator()
+		line(footer, ansiBold)
+	}
+}
@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// tokeiTotal — ключ итоговой записи в выводе `tokei --output json`.
+const tokeiTotal = "Total"
+
+// tokeiLanguageNames сопоставляет названия языков LintVision (они же
+// названия cloc) идентификаторам LanguageType в tokei; совпадающие
+// названия не перечисляются.
+var tokeiLanguageNames = map[string]string{
+	"C++":          "Cpp",
+	"C/C++ Header": "CHeader",
+	"C#":           "CSharp",
+	"PHP":          "Php",
+	"JSON":         "Json",
+	"YAML":         "Yaml",
+	"TOML":         "Toml",
+	"HTML":         "Html",
+	"XML":          "Xml",
+}
+
+type tokeiStats struct {
+	Blanks   int            `json:"blanks"`
+	Code     int            `json:"code"`
+	Comments int            `json:"comments"`
+	Blobs    map[string]any `json:"blobs"`
+}
+
+type tokeiReport struct {
+	Name  string     `json:"name"`
+	Stats tokeiStats `json:"stats"`
+}
+
+type tokeiLanguage struct {
+	Blanks     int                      `json:"blanks"`
+	Code       int                      `json:"code"`
+	Comments   int                      `json:"comments"`
+	Reports    []tokeiReport            `json:"reports"`
+	Children   map[string][]tokeiReport `json:"children"`
+	Inaccurate bool                     `json:"inaccurate"`
+}
+
+// WriteTokei пишет статистику в формате `tokei --output json`: по записи
+// на язык с отчётами по файлам и итоговая запись Total.
+func WriteTokei(w io.Writer, stats ProjectStats) error {
+	out := make(map[string]*tokeiLanguage)
+	total := &tokeiLanguage{Reports: []tokeiReport{}, Children: map[string][]tokeiReport{}}
+	for _, f := range stats.Files {
+		language := toolLanguage(f)
+		if language == "" {
+			continue
+		}
+		name := tokeiLanguageName(language)
+		lang, ok := out[name]
+		if !ok {
+			lang = &tokeiLanguage{Reports: []tokeiReport{}, Children: map[string][]tokeiReport{}}
+			out[name] = lang
+		}
+		blank, comment, code := toolCounts(f)
+		if language == "Markdown" {
+			// tokei считает Markdown литературным языком: текст — это
+			// комментарии.
+			comment, code = comment+code, 0
+		}
+		report := tokeiReport{
+			Name: stats.RelPath(f.Path),
+			Stats: tokeiStats{
+				Blanks:   blank,
+				Code:     code,
+				Comments: comment,
+				Blobs:    map[string]any{},
+			},
+		}
+		lang.Reports = append(lang.Reports, report)
+		lang.Blanks += report.Stats.Blanks
+		lang.Code += report.Stats.Code
+		lang.Comments += report.Stats.Comments
+
+		total.Children[name] = append(total.Children[name], report)
+		total.Blanks += report.Stats.Blanks
+		total.Code += report.Stats.Code
+		total.Comments += report.Stats.Comments
+	}
+	out[tokeiTotal] = total
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func tokeiLanguageName(language string) string {
+	if name, ok := tokeiLanguageNames[language]; ok {
+		return name
+	}
+	return language
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"github.com/rfxxfy/LintVision/external"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// ImportIssues загружает отчёты внешних линтеров и привязывает замечания
+// к файлам проекта; замечания, для которых файл не найден, попадают
+// в UnmatchedIssues.
+func ImportIssues(ps *ProjectStats, reports []string) error {
+	findings, err := external.Collect(reports)
+	if err != nil {
+		return err
+	}
+	byFile, unmatched := matchIssues(newFileMatcher(ps.Root, ps.relPaths()), findings)
+	for i := range ps.Files {
+		ps.Files[i].Issues = takeIssues(byFile, ps.Files[i].Path, ps.RelPath(ps.Files[i].Path))
+	}
+	ps.UnmatchedIssues = unmatched
+	ps.aggregate()
+	logging.Info("ImportIssues: %d findings, %d not matched to project files",
+		len(findings), len(ps.UnmatchedIssues))
+	return nil
+}
+
+// matchIssues группирует замечания по путям файлов относительно корня;
+// замечания без пути или с путём вне проекта возвращаются отдельно.
+func matchIssues(m *fileMatcher, findings []external.Finding) (map[string][]external.Finding, []external.Finding) {
+	byFile := make(map[string][]external.Finding)
+	var unmatched []external.Finding
+	for _, f := range findings {
+		if rel, ok := m.match(f.Path); ok && f.Path != "" {
+			byFile[rel] = append(byFile[rel], f)
+		} else {
+			unmatched = append(unmatched, f)
+		}
+	}
+	return byFile, unmatched
+}
+
+// takeIssues возвращает замечания к файлу filePath (rel — его путь
+// относительно корня) с путём, заменённым на filePath.
+func takeIssues(byFile map[string][]external.Finding, filePath, rel string) []external.Finding {
+	issues := byFile[rel]
+	for i := range issues {
+		issues[i].Path = filePath
+	}
+	return issues
+}
@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// gateEvaluator проверяет quality gates по мере поступления файлов,
+// чтобы их можно было применять и при потоковом выводе.
+type gateEvaluator struct {
+	cfg        gates.Config
+	hashes     map[string]bool
+	dupLines   int
+	totalLines int
+	unknown    int
+	languages  map[string]LineTotals
+	violations []gates.Violation
+}
+
+func newGateEvaluator(cfg gates.Config) *gateEvaluator {
+	return &gateEvaluator{
+		cfg:       cfg,
+		hashes:    make(map[string]bool),
+		languages: make(map[string]LineTotals),
+	}
+}
+
+// add проверяет правила уровня файла и накапливает данные для правил
+// уровня проекта. Сам файл baseline не проверяется.
+func (e *gateEvaluator) add(ps ProjectStats, f FileStats) {
+	if filepath.Base(f.Path) == BaselineFile {
+		return
+	}
+	path := ps.RelPath(f.Path)
+	if limit := e.cfg.MaxLinesPerFile; limit != nil && f.LinesTotal > *limit {
+		e.violations = append(e.violations, gates.Violation{
+			Rule: gates.RuleMaxLinesPerFile, Path: path, Language: f.Language,
+			Actual: float64(f.LinesTotal), Limit: float64(*limit),
+			Message: fmt.Sprintf("%s: %d строк, допустимо не более %d", path, f.LinesTotal, *limit),
+		})
+	}
+	if limit := e.cfg.MaxFileBytes; limit != nil && f.Size > *limit {
+		e.violations = append(e.violations, gates.Violation{
+			Rule: gates.RuleMaxFileBytes, Path: path, Language: f.Language,
+			Actual: float64(f.Size), Limit: float64(*limit),
+			Message: fmt.Sprintf("%s: %d байт, допустимо не более %d", path, f.Size, *limit),
+		})
+	}
+
+	if f.Category == "unknown" {
+		e.unknown++
+	}
+	if f.Language != "" {
+		addLanguage(e.languages, f)
+	}
+	if f.LinesTotal > 0 {
+		e.totalLines += f.LinesTotal
+		if f.Hash != "" {
+			if e.hashes[f.Hash] {
+				e.dupLines += f.LinesTotal
+			}
+			e.hashes[f.Hash] = true
+		}
+	}
+}
+
+// finish проверяет правила уровня проекта и возвращает все нарушения.
+func (e *gateEvaluator) finish() []gates.Violation {
+	for _, lang := range slices.Sorted(maps.Keys(e.languages)) {
+		minRatio, ok := e.cfg.CommentRatio(lang)
+		t := e.languages[lang]
+		if !ok || t.Code+t.Comments == 0 {
+			continue
+		}
+		ratio := float64(t.Comments) / float64(t.Code+t.Comments)
+		if ratio < minRatio {
+			e.violations = append(e.violations, gates.Violation{
+				Rule: gates.RuleMinCommentRatio, Language: lang, Actual: ratio, Limit: minRatio,
+				Message: fmt.Sprintf("%s: доля комментариев %.1f%%, требуется не менее %.1f%%", lang, ratio*100, minRatio*100),
+			})
+		}
+	}
+
+	if limit := e.cfg.MaxDuplicationPercent; limit != nil && e.totalLines > 0 {
+		percent := float64(e.dupLines) * 100 / float64(e.totalLines)
+		if percent > *limit {
+			e.violations = append(e.violations, gates.Violation{
+				Rule: gates.RuleMaxDuplicationPercent, Actual: percent, Limit: *limit,
+				Message: fmt.Sprintf("дублирование %.1f%% строк, допустимо не более %.1f%%", percent, *limit),
+			})
+		}
+	}
+
+	if limit := e.cfg.MaxUnknownFiles; limit != nil && e.unknown > *limit {
+		e.violations = append(e.violations, gates.Violation{
+			Rule: gates.RuleMaxUnknownFiles, Actual: float64(e.unknown), Limit: float64(*limit),
+			Message: fmt.Sprintf("%d файлов неизвестного типа, допустимо не более %d", e.unknown, *limit),
+		})
+	}
+	return e.violations
+}
+
+// EvaluateGates проверяет ps по порогам cfg и сохраняет нарушения
+// в ps.Violations.
+func EvaluateGates(ps *ProjectStats, cfg gates.Config) {
+	e := newGateEvaluator(cfg)
+	for _, f := range ps.Files {
+		e.add(*ps, f)
+	}
+	ps.Violations = e.finish()
+	if len(ps.Violations) > 0 {
+		logging.Warn("EvaluateGates: %d quality gate violations", len(ps.Violations))
+	}
+}
+
+// WriteViolations пишет нарушения quality gates по одному на строку.
+func WriteViolations(w io.Writer, violations []gates.Violation) error {
+	for _, v := range violations {
+		if _, err := fmt.Fprintf(w, "[%s] %s\n", v.Rule, v.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
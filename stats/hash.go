@@ -1,31 +1,59 @@
 package stats
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 )
 
 func ComputeFileHash(path string) (string, error) {
+	sums, err := hashFile(path, sha256.New())
+	if err != nil {
+		return "", err
+	}
+	return sums[0], nil
+}
+
+// computeFileHashes считает SHA256 и SHA1 файла за одно чтение. SHA1
+// нужен только для SPDX, который требует этот алгоритм.
+func computeFileHashes(path string) (sha256Sum, sha1Sum string, err error) {
+	sums, err := hashFile(path, sha256.New(), sha1.New())
+	if err != nil {
+		return "", "", err
+	}
+	return sums[0], sums[1], nil
+}
+
+// hashFile возвращает суммы файла в порядке hashes.
+func hashFile(path string, hashes ...hash.Hash) ([]string, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("open file for hashing: %w", err)
+		return nil, fmt.Errorf("open file for hashing: %w", err)
 	}
 	defer f.Close()
 
 	fi, err := f.Stat()
 	if err != nil {
-		return "", fmt.Errorf("stat file for hashing: %w", err)
+		return nil, fmt.Errorf("stat file for hashing: %w", err)
 	}
 	if !fi.Mode().IsRegular() {
-		return "", fmt.Errorf("not a regular file: %s", path)
+		return nil, fmt.Errorf("not a regular file: %s", path)
 	}
 
-	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", fmt.Errorf("read file for hashing: %w", err)
+	writers := make([]io.Writer, len(hashes))
+	for i, h := range hashes {
+		writers[i] = h
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return nil, fmt.Errorf("read file for hashing: %w", err)
+	}
+	sums := make([]string, len(hashes))
+	for i, h := range hashes {
+		sums[i] = hex.EncodeToString(h.Sum(nil))
 	}
-	return hex.EncodeToString(h.Sum(nil)), nil
+	return sums, nil
 }
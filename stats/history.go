@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// FileHistory — история изменений файла по git. Commits, LinesAdded и
+// LinesRemoved считаются за окно анализа, LastModified — по всей
+// доступной истории. Hotspot — произведение частоты изменений и размера
+// файла, нормированных на максимумы по проекту: 1 у файла, который
+// одновременно самый большой и чаще всех меняется, 0 у файлов, не
+// менявшихся в окне.
+type FileHistory struct {
+	Commits      int       `json:"commits"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	LastModified time.Time `json:"last_modified"`
+	Hotspot      float64   `json:"hotspot"`
+}
+
+// HistoryInfo описывает окно, по которому собрана история.
+type HistoryInfo struct {
+	// Since — начало окна; отсутствует, если учитывалась вся история.
+	Since   *time.Time `json:"since,omitempty"`
+	Commits int        `json:"commits"`
+	// Shallow — репозиторий клонирован не полностью, и старые коммиты
+	// не учтены.
+	Shallow bool `json:"shallow,omitempty"`
+}
+
+// ApplyHistory собирает историю изменений файлов из git и считает
+// hotspot-оценку. days ограничивает окно последними днями; 0 — вся
+// история. Файлы, которых нет в истории (например, неотслеживаемые),
+// остаются без данных.
+func ApplyHistory(ps *ProjectStats, days int) error {
+	var since time.Time
+	if days > 0 {
+		since = time.Now().UTC().AddDate(0, 0, -days).Truncate(time.Second)
+	}
+	churn, err := history.LoadChurn(ps.Root, since)
+	if err != nil {
+		return err
+	}
+	ps.History = &HistoryInfo{Commits: churn.Commits, Shallow: churn.Shallow}
+	if !since.IsZero() {
+		ps.History.Since = &since
+	}
+	for i := range ps.Files {
+		ps.Files[i].History = nil
+		c, ok := churn.Files[ps.RelPath(ps.Files[i].Path)]
+		if !ok {
+			continue
+		}
+		ps.Files[i].History = &FileHistory{
+			Commits:      c.Commits,
+			LinesAdded:   c.Added,
+			LinesRemoved: c.Removed,
+			LastModified: c.LastModified,
+		}
+	}
+	scoreHotspots(ps.Files)
+	if churn.Shallow {
+		logging.Warn("ApplyHistory: %s is a shallow clone, history is truncated", ps.Root)
+	}
+	return nil
+}
+
+// scoreHotspots заполняет FileHistory.Hotspot.
+func scoreHotspots(files []FileStats) {
+	maxCommits, maxCode := 0, 0
+	for _, f := range files {
+		if f.History == nil {
+			continue
+		}
+		maxCommits = max(maxCommits, f.History.Commits)
+		maxCode = max(maxCode, f.LinesCode)
+	}
+	if maxCommits == 0 || maxCode == 0 {
+		return
+	}
+	for _, f := range files {
+		if f.History == nil {
+			continue
+		}
+		score := float64(f.History.Commits) / float64(maxCommits) * float64(f.LinesCode) / float64(maxCode)
+		f.History.Hotspot = math.Round(score*1000) / 1000
+	}
+}
+
+// Hotspots возвращает до n файлов с наибольшей hotspot-оценкой;
+// файлы с нулевой оценкой не включаются. При n <= 0 возвращаются
+// все такие файлы.
+func Hotspots(ps ProjectStats, n int) []FileStats {
+	var files []FileStats
+	for _, f := range ps.Files {
+		if f.History != nil && f.History.Hotspot > 0 {
+			files = append(files, f)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].History.Hotspot != files[j].History.Hotspot {
+			return files[i].History.Hotspot > files[j].History.Hotspot
+		}
+		return files[i].History.Commits > files[j].History.Commits
+	})
+	if n > 0 && len(files) > n {
+		files = files[:n]
+	}
+	return files
+}
@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"strings"
+
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// licenseHeaderLines — сколько первых строк файла просматривается
+// в поисках тега SPDX-License-Identifier.
+const licenseHeaderLines = 30
+
+// licenseReport определяет лицензию проекта по файлам LICENSE/COPYING
+// в корне и сверяет с ней SPDX-теги в заголовках исходников. Без Root
+// лицензию проекта задают самые неглубокие файлы лицензии; несколько
+// разных лицензий объединяются через license.Combine.
+// Сгенерированные и vendored файлы не учитываются: у сторонних
+// зависимостей свои лицензии. Файлы не читаются: используются
+// LicenseMatch и License, собранные ComputeFileStats.
+func (ps ProjectStats) licenseReport() *license.Report {
+	report := &license.Report{}
+	// project — файлы, определяющие лицензию проекта: распознанные файлы
+	// лицензий на наименьшей глубине (в корне, если Root задан).
+	var project []license.LicenseFile
+	projectDepth := -1
+
+	for _, f := range ps.Files {
+		if f.Generated || f.Vendored || !license.IsLicenseFile(f.Path) {
+			continue
+		}
+		lf := license.LicenseFile{Path: f.Path}
+		if f.LicenseMatch != nil {
+			lf.SPDX = f.LicenseMatch.SPDX
+			lf.Confidence = f.LicenseMatch.Confidence
+		}
+		report.LicenseFiles = append(report.LicenseFiles, lf)
+
+		depth := strings.Count(ps.RelPath(f.Path), "/")
+		if lf.SPDX == "" || (ps.Root != "" && depth > 0) {
+			continue
+		}
+		switch {
+		case projectDepth < 0 || depth < projectDepth:
+			project = []license.LicenseFile{lf}
+			projectDepth = depth
+		case depth == projectDepth:
+			project = append(project, lf)
+		}
+	}
+	ids := make([]string, len(project))
+	isProject := make(map[string]bool)
+	for i, lf := range project {
+		ids[i] = lf.SPDX
+		isProject[lf.Path] = true
+	}
+	report.Project = license.Combine(ids)
+
+	for _, lf := range report.LicenseFiles {
+		if report.Project != "" && lf.SPDX != "" && !isProject[lf.Path] && !license.Overlaps(lf.SPDX, report.Project) {
+			report.Conflicts = append(report.Conflicts, license.Conflict{
+				Path: lf.Path, License: lf.SPDX, Expected: report.Project,
+			})
+		}
+	}
+
+	for _, f := range ps.Files {
+		if f.Category != "code" || f.Generated || f.Vendored {
+			continue
+		}
+		if f.License == "" {
+			report.MissingHeader = append(report.MissingHeader, f.Path)
+			continue
+		}
+		if report.Project != "" && !license.Overlaps(f.License, report.Project) {
+			report.Conflicts = append(report.Conflicts, license.Conflict{
+				Path: f.Path, License: f.License, Expected: report.Project,
+			})
+		}
+	}
+
+	logging.Info("licenseReport: project license %q, %d files without header, %d conflicts",
+		report.Project, len(report.MissingHeader), len(report.Conflicts))
+	return report
+}
@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fileMatcher сопоставляет пути из внешних отчётов (линтеров, покрытия)
+// файлам проекта по путям относительно корня.
+type fileMatcher struct {
+	files map[string]bool
+	// absRoot — абсолютный путь корня в slash-нотации с "/" на конце.
+	absRoot string
+	// modules — пути модулей Go из go.mod проекта и директории этих
+	// go.mod относительно корня ("." для корня).
+	modules map[string]string
+}
+
+func newFileMatcher(root string, rels []string) *fileMatcher {
+	m := &fileMatcher{files: make(map[string]bool, len(rels)), modules: make(map[string]string)}
+	for _, rel := range rels {
+		m.files[rel] = true
+		if path.Base(rel) == "go.mod" {
+			if mod := goModulePath(filepath.Join(root, filepath.FromSlash(rel))); mod != "" {
+				m.modules[mod] = path.Dir(rel)
+			}
+		}
+	}
+	if root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			m.absRoot = strings.TrimSuffix(filepath.ToSlash(abs), "/") + "/"
+		}
+	}
+	return m
+}
+
+// match возвращает путь файла проекта для пути p из отчёта (в
+// slash-нотации). Относительные пути и абсолютные пути внутри корня
+// должны совпадать с путём файла точно; относительный путь может
+// начинаться с пути модуля Go из go.mod проекта, как в профилях go test.
+// Абсолютные пути вне корня (отчёт получен в другой рабочей копии,
+// например в CI) сопоставляются по окончанию: путь файла относительно
+// корня должен целиком быть окончанием p, выбирается самый длинный.
+func (m *fileMatcher) match(p string) (string, bool) {
+	if p == "" {
+		return "", false
+	}
+	p = path.Clean(p)
+	if m.files[p] {
+		return p, true
+	}
+	if !isAbsSlash(p) {
+		return m.matchModule(p)
+	}
+	if m.absRoot != "" && strings.HasPrefix(p, m.absRoot) {
+		rel := strings.TrimPrefix(p, m.absRoot)
+		return rel, m.files[rel]
+	}
+	for i := strings.Index(p, "/"); i >= 0; i = strings.Index(p, "/") {
+		p = p[i+1:]
+		if m.files[p] {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// matchModule сопоставляет путь вида модуль/пакет/файл.go файлу
+// модуля Go; при вложенных модулях выбирается самый длинный путь модуля.
+func (m *fileMatcher) matchModule(p string) (string, bool) {
+	best := ""
+	for mod := range m.modules {
+		if strings.HasPrefix(p, mod+"/") && len(mod) > len(best) {
+			best = mod
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	rel := path.Join(m.modules[best], strings.TrimPrefix(p, best+"/"))
+	return rel, m.files[rel]
+}
+
+// isAbsSlash сообщает, абсолютен ли путь в slash-нотации,
+// включая пути Windows вида C:/src.
+func isAbsSlash(p string) bool {
+	return strings.HasPrefix(p, "/") || (len(p) > 2 && p[1] == ':' && p[2] == '/')
+}
+
+// goModulePath возвращает путь модуля из директивы module файла go.mod
+// или пустую строку, если его не удалось прочитать.
+func goModulePath(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if mod, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`)
+		}
+	}
+	return ""
+}
+
+// relPaths возвращает пути файлов относительно Root.
+func (ps ProjectStats) relPaths() []string {
+	rels := make([]string, len(ps.Files))
+	for i, f := range ps.Files {
+		rels[i] = ps.RelPath(f.Path)
+	}
+	return rels
+}
@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"context"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// ToolVersion — версия LintVision, попадающая в метаданные отчётов.
+// Задаётся при сборке через
+// -ldflags "-X github.com/rfxxfy/LintVision/stats.ToolVersion=v1.2.3",
+// иначе берётся из информации о сборке модуля.
+var ToolVersion = "dev"
+
+func init() {
+	if ToolVersion != "dev" {
+		return
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		ToolVersion = info.Main.Version
+	}
+}
+
+// Metadata описывает, как и когда был получен отчёт.
+type Metadata struct {
+	Tool        string    `json:"tool"`
+	ToolVersion string    `json:"tool_version"`
+	Commit      string    `json:"commit,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Duration    float64   `json:"duration_seconds"`
+	Options     Options   `json:"options"`
+}
+
+// newMetadata заполняет метаданные анализа, начатого в started.
+func newMetadata(root string, opts Options, started time.Time) *Metadata {
+	return &Metadata{
+		Tool:        sbomToolName,
+		ToolVersion: ToolVersion,
+		Commit:      gitCommit(root),
+		Timestamp:   started.UTC().Truncate(time.Second),
+		Duration:    time.Since(started).Seconds(),
+		Options:     opts,
+	}
+}
+
+// gitCommit возвращает SHA текущего коммита, если root — git-репозиторий.
+func gitCommit(root string) string {
+	if root == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", root, "rev-parse", "HEAD").Output()
+	if err != nil {
+		logging.Info("gitCommit: %s is not a git checkout: %v", root, err)
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// Options задаёт формат вывода и дополнительные проходы анализа.
+// Options сохраняются в метаданных отчёта; настройки вывода
+// в терминал в JSON не попадают.
+type Options struct {
+	Format string `json:"format,omitempty"`
+	// Compare — путь к прежнему JSON-отчёту для показа изменений в Markdown.
+	Compare string `json:"compare,omitempty"`
+
+	// Print — формат вывода в stdout: FormatTable, любой формат экспорта
+	// или пустая строка, чтобы ничего не печатать.
+	Print string       `json:"-"`
+	Table TableOptions `json:"-"`
+
+	// ExcludeGenerated убирает из отчёта сгенерированные и vendored файлы.
+	ExcludeGenerated bool `json:"exclude_generated,omitempty"`
+
+	Secrets          bool   `json:"secrets,omitempty"`
+	SecretsAllowlist string `json:"secrets_allowlist,omitempty"`
+
+	// Baseline — файл со снимком метрик (по умолчанию BaselineFile в
+	// корне проекта, если он есть); UpdateBaseline перезаписывает его.
+	Baseline       string `json:"baseline,omitempty"`
+	UpdateBaseline bool   `json:"update_baseline,omitempty"`
+
+	// Import — отчёты внешних линтеров (checkstyle XML, SARIF, ESLint
+	// JSON, golangci-lint JSON), замечания из которых привязываются к файлам.
+	Import []string `json:"import,omitempty"`
+	// Coverage — файлы покрытия тестами (Go coverprofile, LCOV, Cobertura XML).
+	Coverage []string `json:"coverage,omitempty"`
+
+	// History собирает историю изменений файлов из git (нужен git
+	// в PATH); HistoryDays ограничивает её последними днями, 0 — вся история.
+	History     bool `json:"history,omitempty"`
+	HistoryDays int  `json:"history_days,omitempty"`
+	// Blame считает авторство строк через git blame; Mailmap —
+	// дополнительный файл .mailmap для сопоставления email авторов.
+	Blame   bool   `json:"blame,omitempty"`
+	Mailmap string `json:"mailmap,omitempty"`
+}
+
+// Analyze считает статистику по директории и выполняет включённые
+// в opts дополнительные проходы.
+func Analyze(root string, opts Options) (ProjectStats, error) {
+	started := time.Now()
+	ps, err := ComputeProjectStatsFromDir(root)
+	if err != nil {
+		return ps, err
+	}
+	if len(opts.Import) > 0 {
+		if err := ImportIssues(&ps, opts.Import); err != nil {
+			logging.Error("Analyze: import failed: %v", err)
+			return ps, err
+		}
+	}
+	if len(opts.Coverage) > 0 {
+		if err := ApplyCoverage(&ps, opts.Coverage); err != nil {
+			logging.Error("Analyze: coverage failed: %v", err)
+			return ps, err
+		}
+	}
+	if opts.ExcludeGenerated {
+		ps.DropGenerated()
+	}
+	if opts.History {
+		if err := ApplyHistory(&ps, opts.HistoryDays); err != nil {
+			logging.Error("Analyze: history failed: %v", err)
+			return ps, err
+		}
+	}
+	if opts.Blame {
+		if err := ApplyBlame(&ps, opts.Mailmap); err != nil {
+			logging.Error("Analyze: blame failed: %v", err)
+			return ps, err
+		}
+	}
+	if opts.Secrets {
+		if err := ScanSecrets(&ps, opts.SecretsAllowlist); err != nil {
+			logging.Error("Analyze: secrets scan failed: %v", err)
+			return ps, err
+		}
+	}
+	EvaluateGates(&ps, gates.Current)
+	if err := applyBaseline(&ps, opts); err != nil {
+		logging.Error("Analyze: baseline failed: %v", err)
+		return ps, err
+	}
+	ps.Metadata = newMetadata(root, opts, started)
+	return ps, nil
+}
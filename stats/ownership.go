@@ -0,0 +1,141 @@
+package stats
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// busFactorShare — доля строк, которой должны владеть авторы, чтобы
+// их уход сделал код «бесхозным» (см. Ownership.BusFactor).
+const busFactorShare = 0.5
+
+// Ownership — авторство строк по данным git blame.
+type Ownership struct {
+	Lines int `json:"lines"`
+	// BusFactor — минимальное число авторов, которым вместе принадлежит
+	// больше половины строк.
+	BusFactor int                   `json:"bus_factor"`
+	Authors   []history.AuthorLines `json:"authors"`
+}
+
+// OwnershipReport — авторство по всему проекту и по директориям
+// верхнего уровня.
+type OwnershipReport struct {
+	Ownership
+	ByDir map[string]Ownership `json:"by_dir"`
+}
+
+// ApplyBlame считает авторство строк через git blame. Учитываются
+// отслеживаемые git файлы с исходным кодом, кроме сгенерированных и
+// vendored. mailmap — дополнительный файл .mailmap для сопоставления
+// email авторов; .mailmap в корне репозитория git применяет сам.
+func ApplyBlame(ps *ProjectStats, mailmap string) error {
+	tracked, err := history.TrackedFiles(ps.Root)
+	if err != nil {
+		return err
+	}
+	if mailmap != "" {
+		if mailmap, err = filepath.Abs(mailmap); err != nil {
+			return err
+		}
+	}
+	blamed := 0
+	for i := range ps.Files {
+		f := &ps.Files[i]
+		f.Authors = nil
+		rel := ps.RelPath(f.Path)
+		if f.Category != "code" || f.Generated || f.Vendored || !tracked[rel] {
+			continue
+		}
+		if f.Authors, err = history.Blame(ps.Root, rel, mailmap); err != nil {
+			return err
+		}
+		blamed++
+	}
+	ps.aggregate()
+	logging.Info("ApplyBlame: blamed %d files", blamed)
+	return nil
+}
+
+// ownership сводит авторство файлов по проекту и директориям; nil,
+// если авторство не считалось.
+func (ps ProjectStats) ownership() *OwnershipReport {
+	total := make(map[string]history.AuthorLines)
+	byDir := make(map[string]map[string]history.AuthorLines)
+	for _, f := range ps.Files {
+		if len(f.Authors) == 0 {
+			continue
+		}
+		dir := ps.topDir(f.Path)
+		if byDir[dir] == nil {
+			byDir[dir] = make(map[string]history.AuthorLines)
+		}
+		for _, a := range f.Authors {
+			addAuthorLines(total, a)
+			addAuthorLines(byDir[dir], a)
+		}
+	}
+	if len(total) == 0 {
+		return nil
+	}
+	report := &OwnershipReport{Ownership: newOwnership(total), ByDir: make(map[string]Ownership, len(byDir))}
+	for dir, authors := range byDir {
+		report.ByDir[dir] = newOwnership(authors)
+	}
+	return report
+}
+
+// addAuthorLines добавляет строки автора; авторы сопоставляются
+// по email, как в history.Blame.
+func addAuthorLines(m map[string]history.AuthorLines, a history.AuthorLines) {
+	key := strings.ToLower(a.Email)
+	v, ok := m[key]
+	if !ok {
+		v = history.AuthorLines{Name: a.Name, Email: a.Email}
+	}
+	v.Lines += a.Lines
+	m[key] = v
+}
+
+func newOwnership(byEmail map[string]history.AuthorLines) Ownership {
+	var o Ownership
+	for _, a := range byEmail {
+		o.Authors = append(o.Authors, a)
+		o.Lines += a.Lines
+	}
+	history.SortAuthors(o.Authors)
+	owned := 0
+	for _, a := range o.Authors {
+		if float64(owned) > float64(o.Lines)*busFactorShare {
+			break
+		}
+		owned += a.Lines
+		o.BusFactor++
+	}
+	return o
+}
+
+// DirsByRisk возвращает директории верхнего уровня отчёта об авторстве
+// в порядке возрастания bus factor, а при равенстве — убывания числа
+// строк: сначала код, который держится на меньшем числе людей.
+func (r OwnershipReport) DirsByRisk() []string {
+	dirs := make([]string, 0, len(r.ByDir))
+	for dir := range r.ByDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		a, b := r.ByDir[dirs[i]], r.ByDir[dirs[j]]
+		if a.BusFactor != b.BusFactor {
+			return a.BusFactor < b.BusFactor
+		}
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return dirs[i] < dirs[j]
+	})
+	return dirs
+}
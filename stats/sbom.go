@@ -0,0 +1,265 @@
+package stats
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+const sbomToolName = "LintVision"
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type    string    `json:"type"`
+	BOMRef  string    `json:"bom-ref,omitempty"`
+	Name    string    `json:"name"`
+	Version string    `json:"version,omitempty"`
+	Scope   string    `json:"scope,omitempty"`
+	PURL    string    `json:"purl,omitempty"`
+	Hashes  []cdxHash `json:"hashes,omitempty"`
+
+	Licenses []cdxLicense `json:"licenses,omitempty"`
+}
+
+type cdxLicense struct {
+	Expression string `json:"expression"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// WriteCycloneDX пишет SBOM в формате CycloneDX 1.5 JSON: зависимости
+// из манифестов и все проанализированные файлы с их SHA-256.
+func WriteCycloneDX(w io.Writer, stats ProjectStats) error {
+	rootRef := "root"
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: sbomToolName, Version: ToolVersion},
+			}},
+			Component: cdxComponent{Type: "application", BOMRef: rootRef, Name: stats.Name()},
+		},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+
+	if stats.Licenses != nil && stats.Licenses.Project != "" {
+		bom.Metadata.Component.Licenses = []cdxLicense{{Expression: stats.Licenses.Project}}
+	}
+
+	direct := cdxDependency{Ref: rootRef, DependsOn: []string{}}
+	seen := make(map[string]bool)
+	for _, d := range stats.Dependencies {
+		purl := d.PURL()
+		if seen[purl] {
+			continue
+		}
+		seen[purl] = true
+
+		c := cdxComponent{Type: "library", BOMRef: purl, Name: d.Name, Version: d.Version, PURL: purl}
+		if d.Dev {
+			c.Scope = "optional"
+		}
+		bom.Components = append(bom.Components, c)
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: purl, DependsOn: []string{}})
+		if !d.Indirect {
+			direct.DependsOn = append(direct.DependsOn, purl)
+		}
+	}
+	bom.Dependencies = append([]cdxDependency{direct}, bom.Dependencies...)
+
+	for _, f := range stats.Files {
+		c := cdxComponent{Type: "file", Name: stats.RelPath(f.Path)}
+		if f.Hash != "" {
+			c.Hashes = []cdxHash{{Alg: "SHA-256", Content: f.Hash}}
+		}
+		if f.License != "" {
+			c.Licenses = []cdxLicense{{Expression: f.License}}
+		}
+		bom.Components = append(bom.Components, c)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bom)
+}
+
+var spdxIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// WriteSPDX пишет SBOM в формате SPDX 2.3 tag-value. SPDX требует для
+// перечисленных файлов SHA1 и код проверки пакета, поэтому файлы
+// перечитываются; если какой-то из них недоступен (например, отчёт
+// загружен из JSON), пакет описывается без файлов.
+func WriteSPDX(w io.Writer, stats ProjectStats) error {
+	checksums, filesAnalyzed := spdxChecksums(stats)
+	bw := bufio.NewWriter(w)
+	name := stats.Name()
+	pkgID := "SPDXRef-Package-" + spdxIDUnsafe.ReplaceAllString(name, "-")
+	downloadLocation := "NOASSERTION"
+	if stats.Source != "" {
+		downloadLocation = "git+" + stats.Source
+	}
+
+	fmt.Fprintln(bw, "SPDXVersion: SPDX-2.3")
+	fmt.Fprintln(bw, "DataLicense: CC0-1.0")
+	fmt.Fprintln(bw, "SPDXID: SPDXRef-DOCUMENT")
+	fmt.Fprintf(bw, "DocumentName: %s\n", name)
+	fmt.Fprintf(bw, "DocumentNamespace: https://spdx.org/spdxdocs/%s-%s\n",
+		spdxIDUnsafe.ReplaceAllString(name, "-"), newUUID())
+	fmt.Fprintf(bw, "Creator: Tool: %s-%s\n", sbomToolName, ToolVersion)
+	fmt.Fprintf(bw, "Created: %s\n", time.Now().UTC().Format(time.RFC3339))
+
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "PackageName: %s\n", name)
+	fmt.Fprintf(bw, "SPDXID: %s\n", pkgID)
+	fmt.Fprintf(bw, "PackageDownloadLocation: %s\n", downloadLocation)
+	fmt.Fprintf(bw, "FilesAnalyzed: %t\n", filesAnalyzed)
+	if filesAnalyzed {
+		fmt.Fprintf(bw, "PackageVerificationCode: %s\n", verificationCode(checksums))
+	}
+	fmt.Fprintln(bw, "PackageLicenseConcluded: NOASSERTION")
+	fmt.Fprintf(bw, "PackageLicenseDeclared: %s\n", projectLicense(stats))
+	fmt.Fprintln(bw, "PackageCopyrightText: NOASSERTION")
+
+	var relationships []string
+	relationships = append(relationships, "SPDXRef-DOCUMENT DESCRIBES "+pkgID)
+
+	seen := make(map[string]bool)
+	for i, d := range stats.Dependencies {
+		purl := d.PURL()
+		if seen[purl] {
+			continue
+		}
+		seen[purl] = true
+
+		depID := fmt.Sprintf("SPDXRef-Dependency-%d", i+1)
+		version := d.Version
+		if version == "" {
+			version = "NOASSERTION"
+		}
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "PackageName: %s\n", d.Name)
+		fmt.Fprintf(bw, "SPDXID: %s\n", depID)
+		fmt.Fprintf(bw, "PackageVersion: %s\n", version)
+		fmt.Fprintln(bw, "PackageDownloadLocation: NOASSERTION")
+		fmt.Fprintln(bw, "FilesAnalyzed: false")
+		fmt.Fprintln(bw, "PackageLicenseConcluded: NOASSERTION")
+		fmt.Fprintln(bw, "PackageLicenseDeclared: NOASSERTION")
+		fmt.Fprintln(bw, "PackageCopyrightText: NOASSERTION")
+		fmt.Fprintf(bw, "ExternalRef: PACKAGE-MANAGER purl %s\n", purl)
+		relationships = append(relationships, pkgID+" DEPENDS_ON "+depID)
+	}
+
+	for i, f := range stats.Files {
+		if !filesAnalyzed {
+			break
+		}
+		fileID := fmt.Sprintf("SPDXRef-File-%d", i+1)
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "FileName: ./%s\n", stats.RelPath(f.Path))
+		fmt.Fprintf(bw, "SPDXID: %s\n", fileID)
+		fmt.Fprintf(bw, "FileChecksum: SHA1: %s\n", checksums[i])
+		if f.Hash != "" {
+			fmt.Fprintf(bw, "FileChecksum: SHA256: %s\n", f.Hash)
+		}
+		fmt.Fprintln(bw, "LicenseConcluded: NOASSERTION")
+		// LicenseInfoInFile принимает только отдельные идентификаторы,
+		// поэтому выражение из заголовка раскладывается на них.
+		for _, id := range license.IDs(f.License) {
+			fmt.Fprintf(bw, "LicenseInfoInFile: %s\n", id)
+		}
+		fmt.Fprintln(bw, "FileCopyrightText: NOASSERTION")
+		relationships = append(relationships, pkgID+" CONTAINS "+fileID)
+	}
+
+	fmt.Fprintln(bw)
+	for _, r := range relationships {
+		fmt.Fprintf(bw, "Relationship: %s\n", r)
+	}
+	return bw.Flush()
+}
+
+// spdxChecksums возвращает SHA1 файлов отчёта в порядке Files; ok ==
+// false, если у какого-то файла SHA1 нет (отчёт старой версии схемы).
+// Файлы не читаются: к моменту экспорта их может уже не быть, например
+// после анализа временного клона репозитория.
+func spdxChecksums(stats ProjectStats) ([]string, bool) {
+	checksums := make([]string, len(stats.Files))
+	for i, f := range stats.Files {
+		if f.SHA1 == "" {
+			logging.Warn("WriteSPDX: files are not listed: no SHA1 for %s", f.Path)
+			return nil, false
+		}
+		checksums[i] = f.SHA1
+	}
+	return checksums, true
+}
+
+// verificationCode считает PackageVerificationCode по SPDX 2.3: SHA1
+// от отсортированных и склеенных SHA1 файлов пакета.
+func verificationCode(checksums []string) string {
+	sorted := append([]string(nil), checksums...)
+	sort.Strings(sorted)
+	sum := sha1.Sum([]byte(strings.Join(sorted, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+func projectLicense(stats ProjectStats) string {
+	if stats.Licenses != nil && stats.Licenses.Project != "" {
+		return stats.Licenses.Project
+	}
+	return "NOASSERTION"
+}
+
+// newUUID генерирует случайный UUID версии 4.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,317 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rfxxfy/LintVision/classify"
+	"github.com/rfxxfy/LintVision/extensions"
+)
+
+// SchemaVersion — текущая версия формата JSON-отчёта. Её нужно
+// увеличивать при любом изменении полей и описывать миграцию в DecodeStats.
+//
+//	1 — исходный формат без schema_version: files, category_counts, hidden_*.
+//	2 — языки, итоги, классификация файлов, лицензии, секреты, метаданные.
+//	3 — размер файла (size) и нарушения quality gates (violations).
+//	4 — находки правил стиля (files[].lint, lint_counts).
+//	5 — замечания внешних линтеров (files[].issues, issue_counts и др.).
+//	6 — покрытие тестами (files[].coverage, coverage_totals и др.).
+//	7 — история изменений по git (files[].history, history).
+//	8 — авторство по git blame (files[].authors, ownership).
+//	9 — владельцы из CODEOWNERS (files[].owners, codeowners).
+//	10 — лицензия файлов лицензий (files[].license_match).
+//	11 — SHA1 файлов для SPDX (files[].sha1).
+const SchemaVersion = 11
+
+// DecodeStats разбирает JSON-отчёт. Отчёты более новой версии, чем
+// SchemaVersion, не принимаются. Отчёты старых версий дополняются полями,
+// которые можно вычислить по списку файлов; остальных данных в них нет:
+//   - до версии 3 — размера файлов (остаётся нулевым);
+//   - до версии 4 — находок правил стиля;
+//   - до версии 5 — замечаний внешних линтеров;
+//   - до версии 6 — покрытия тестами;
+//   - до версии 7 — истории изменений;
+//   - до версии 8 — авторства строк;
+//   - до версии 9 — владельцев из CODEOWNERS;
+//   - до версии 10 — лицензий, распознанных по тексту файлов лицензий;
+//   - до версии 11 — SHA1 файлов, поэтому SPDX перечисляет файлы
+//     только для новых отчётов.
+func DecodeStats(data []byte) (ProjectStats, error) {
+	var stats ProjectStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return stats, err
+	}
+	switch {
+	case stats.SchemaVersion > SchemaVersion:
+		return stats, fmt.Errorf("schema version %d is newer than supported %d", stats.SchemaVersion, SchemaVersion)
+	case stats.SchemaVersion <= 1:
+		migrateV1(&stats)
+	}
+	stats.SchemaVersion = SchemaVersion
+	return stats, nil
+}
+
+// migrateV1 восстанавливает язык и классификацию файлов по путям и
+// пересчитывает итоги. Лицензии не пересчитываются: для этого нужны
+// сами файлы.
+func migrateV1(stats *ProjectStats) {
+	for i := range stats.Files {
+		f := &stats.Files[i]
+		if f.Language == "" {
+			f.Language = extensions.GetLanguageName(f.Ext)
+		}
+		f.Generated = classify.IsGeneratedPath(f.Path)
+		f.Vendored = classify.IsVendored(f.Path)
+		f.Test = classify.IsTestFile(f.Path, f.Language)
+	}
+	stats.resetTotals()
+	for _, f := range stats.Files {
+		stats.addTotals(f)
+	}
+}
+
+// schemaDescriptions — описания типов и полей для JSON Schema;
+// ключ — имя типа или "Тип.json_поле".
+var schemaDescriptions = map[string]string{
+	"ProjectStats":                        "Отчёт LintVision по проекту.",
+	"ProjectStats.schema_version":         "Версия формата отчёта.",
+	"ProjectStats.metadata":               "Как и когда получен отчёт.",
+	"ProjectStats.root":                   "Анализируемая директория.",
+	"ProjectStats.source":                 "URL репозитория, если анализировался удалённый проект.",
+	"ProjectStats.files":                  "Статистика по каждому файлу.",
+	"ProjectStats.category_counts":        "Число файлов по категориям.",
+	"ProjectStats.marker_counts":          "Число маркеров TODO/FIXME по видам.",
+	"ProjectStats.totals":                 "Итоги по всем файлам.",
+	"ProjectStats.generated_totals":       "Итоги по сгенерированным файлам.",
+	"ProjectStats.vendored_totals":        "Итоги по vendored файлам.",
+	"ProjectStats.test_totals":            "Итоги по тестам.",
+	"ProjectStats.non_test_totals":        "Итоги по нетестовому коду.",
+	"ProjectStats.hidden_files":           "Число скрытых файлов.",
+	"ProjectStats.hidden_dirs":            "Число скрытых директорий.",
+	"ProjectStats.non_hidden_dirs":        "Число нескрытых директорий.",
+	"FileStats":                           "Статистика одного файла.",
+	"FileStats.path":                      "Путь к файлу в том виде, в каком он был найден при обходе.",
+	"FileStats.ext":                       "Расширение файла с точкой.",
+	"FileStats.category":                  "Категория файла (code, markup, image и т. д.).",
+	"FileStats.language":                  "Язык программирования.",
+	"FileStats.lines_comments":            "Строки с комментариями, включая комментарии после кода.",
+	"FileStats.lint":                      "Находки правил стиля.",
+	"ProjectStats.lint_counts":            "Число находок правил стиля по ID правила.",
+	"FileStats.issues":                    "Замечания внешних линтеров.",
+	"ProjectStats.issue_counts":           "Число замечаний внешних линтеров по инструментам.",
+	"ProjectStats.issues_by_language":     "Число замечаний внешних линтеров по языкам.",
+	"ProjectStats.issues_by_dir":          "Число замечаний внешних линтеров по директориям верхнего уровня.",
+	"ProjectStats.unmatched_issues":       "Замечания внешних линтеров, не привязанные к файлам проекта.",
+	"ExternalFinding":                     "Замечание внешнего линтера из импортированного отчёта.",
+	"Coverage":                            "Покрытие исполняемых строк тестами.",
+	"Coverage.percent":                    "Доля покрытых строк в процентах.",
+	"FileStats.coverage":                  "Покрытие файла тестами; отсутствует, если файла нет в файлах покрытия.",
+	"ProjectStats.coverage_totals":        "Покрытие по всем файлам с данными о покрытии.",
+	"ProjectStats.coverage_by_language":   "Покрытие по языкам.",
+	"ProjectStats.coverage_by_dir":        "Покрытие по директориям верхнего уровня.",
+	"FileHistory":                         "История изменений файла по git.",
+	"FileHistory.commits":                 "Число коммитов в окне анализа, изменивших файл.",
+	"FileHistory.lines_added":             "Добавлено строк за окно анализа.",
+	"FileHistory.lines_removed":           "Удалено строк за окно анализа.",
+	"FileHistory.last_modified":           "Время последнего коммита, изменившего файл.",
+	"FileHistory.hotspot":                 "Произведение частоты изменений и размера файла, нормированных на максимумы по проекту (0–1).",
+	"FileStats.history":                   "История изменений файла; отсутствует, если история не собиралась или файла нет в git.",
+	"HistoryInfo":                         "Окно, по которому собрана история изменений.",
+	"HistoryInfo.since":                   "Начало окна; отсутствует, если учитывалась вся история.",
+	"HistoryInfo.commits":                 "Число коммитов в окне.",
+	"HistoryInfo.shallow":                 "Репозиторий клонирован не полностью, старые коммиты не учтены.",
+	"ProjectStats.history":                "Параметры сбора истории изменений.",
+	"AuthorLines":                         "Строки, последним изменённые автором (после применения .mailmap).",
+	"AuthorLines.lines":                   "Число строк автора.",
+	"FileStats.authors":                   "Авторы строк файла по git blame, по убыванию числа строк.",
+	"Ownership":                           "Авторство строк по git blame.",
+	"Ownership.lines":                     "Число учтённых строк.",
+	"Ownership.bus_factor":                "Минимальное число авторов, которым вместе принадлежит больше половины строк.",
+	"OwnershipReport":                     "Авторство по проекту и директориям верхнего уровня.",
+	"OwnershipReport.lines":               "Число учтённых строк по проекту.",
+	"OwnershipReport.bus_factor":          "Bus factor проекта: минимальное число авторов, которым вместе принадлежит больше половины строк.",
+	"OwnershipReport.authors":             "Авторы по проекту, по убыванию числа строк.",
+	"OwnershipReport.by_dir":              "Авторство по директориям верхнего уровня.",
+	"ProjectStats.ownership":              "Авторство строк по git blame.",
+	"FileStats.owners":                    "Владельцы файла по CODEOWNERS; отсутствует, если владельцев нет.",
+	"CodeOwnersReport":                    "Покрытие проекта владельцами из CODEOWNERS.",
+	"CodeOwnersReport.file":               "Путь к CODEOWNERS относительно корня.",
+	"CodeOwnersReport.by_owner":           "Итоги по владельцам; файл с несколькими владельцами учитывается у каждого.",
+	"CodeOwnersReport.unowned":            "Файлы без владельцев.",
+	"CodeOwnersReport.unowned_totals":     "Итоги по файлам без владельцев.",
+	"ProjectStats.codeowners":             "Владельцы из CODEOWNERS; отсутствует, если файла CODEOWNERS нет.",
+	"FileStats.size":                      "Размер файла в байтах.",
+	"FileStats.hash":                      "SHA-256 содержимого в hex.",
+	"FileStats.license":                   "SPDX-выражение из заголовка файла.",
+	"LineTotals":                          "Суммарное число файлов и строк.",
+	"TestRatio":                           "Соотношение строк кода в тестах и вне их.",
+	"Metadata":                            "Метаданные анализа.",
+	"Metadata.tool_version":               "Версия LintVision.",
+	"Metadata.commit":                     "SHA коммита анализируемого репозитория.",
+	"Metadata.timestamp":                  "Время начала анализа (UTC).",
+	"Metadata.duration_seconds":           "Длительность анализа в секундах.",
+	"Metadata.options":                    "Параметры анализа.",
+	"Dependency":                          "Зависимость из манифеста пакетного менеджера.",
+	"Marker":                              "Маркер TODO/FIXME в комментарии.",
+	"Report":                              "Лицензии проекта и несоответствия им.",
+	"Violation":                           "Нарушение правила quality gates.",
+	"ProjectStats.violations":             "Нарушения quality gates.",
+	"LintFinding":                         "Нарушение правила стиля.",
+	"SecretsFinding":                      "Вероятный секрет в исходном коде.",
+	"ProjectStats.dependencies":           "Зависимости из манифестов.",
+	"ProjectStats.licenses":               "Отчёт о лицензиях.",
+	"ProjectStats.secrets":                "Найденные секреты.",
+	"ProjectStats.test_ratio_by_dir":      "Соотношение тестов и кода по директориям верхнего уровня.",
+	"ProjectStats.test_ratio_by_language": "Соотношение тестов и кода по языкам.",
+}
+
+// JSONSchema строит JSON Schema (draft 2020-12) отчёта по Go-типам
+// ProjectStats.
+func JSONSchema() ([]byte, error) {
+	root := reflect.TypeOf(ProjectStats{})
+	g := schemaGenerator{defs: make(map[string]any), names: make(map[reflect.Type]string)}
+	g.nameTypes(root)
+	ref := g.schemaFor(root, false)
+	doc := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "LintVision report",
+		"description": fmt.Sprintf("Отчёт LintVision, версия схемы %d.", SchemaVersion),
+		"$ref":        ref["$ref"],
+		"$defs":       g.defs,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type schemaGenerator struct {
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+// nameTypes выбирает имена в $defs для всех структур, достижимых из root.
+// Одноимённые типы из разных пакетов получают префикс пакета:
+// lint.Finding -> LintFinding.
+func (g *schemaGenerator) nameTypes(root reflect.Type) {
+	byName := make(map[string][]reflect.Type)
+	seen := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+			return
+		}
+		seen[t] = true
+		byName[t.Name()] = append(byName[t.Name()], t)
+		for i := 0; i < t.NumField(); i++ {
+			walk(t.Field(i).Type)
+		}
+	}
+	walk(root)
+
+	for name, types := range byName {
+		for _, t := range types {
+			if len(types) == 1 {
+				g.names[t] = name
+				continue
+			}
+			pkg := path.Base(t.PkgPath())
+			g.names[t] = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor возвращает схему значения типа t; структуры выносятся
+// в $defs. nullable — может ли значение быть null (срезы и карты без omitempty).
+func (g *schemaGenerator) schemaFor(t reflect.Type, nullable bool) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem(), false)
+	case reflect.Struct:
+		name := g.names[t]
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // защита от рекурсии
+			g.defs[name] = g.structSchema(t, name)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	case reflect.Slice:
+		return map[string]any{"type": nullableType("array", nullable), "items": g.schemaFor(t.Elem(), false)}
+	case reflect.Map:
+		return map[string]any{"type": nullableType("object", nullable), "additionalProperties": g.schemaFor(t.Elem(), false)}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
+
+func nullableType(typ string, nullable bool) any {
+	if nullable {
+		return []string{typ, "null"}
+	}
+	return typ
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type, name string) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	g.addFields(t, name, props, &required)
+
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if d, ok := schemaDescriptions[name]; ok {
+		s["description"] = d
+	}
+	return s
+}
+
+// addFields добавляет в props поля структуры t так же, как их
+// сериализует encoding/json, включая поля встроенных структур.
+func (g *schemaGenerator) addFields(t reflect.Type, owner string, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(field.Type, owner, props, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+		prop := g.schemaFor(field.Type, !omitempty)
+		if d, ok := schemaDescriptions[owner+"."+name]; ok {
+			prop["description"] = d
+		}
+		props[name] = prop
+		if !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
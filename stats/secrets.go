@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rfxxfy/LintVision/logging"
+	"github.com/rfxxfy/LintVision/secrets"
+)
+
+// ScanSecrets ищет в файлах проекта вероятные секреты и сохраняет находки
+// в ps.Secrets. Если allowlistPath пуст, используется файл
+// secrets.AllowlistFile из корня проекта (при его наличии).
+func ScanSecrets(ps *ProjectStats, allowlistPath string) error {
+	allow, err := loadSecretsAllowlist(ps.Root, allowlistPath)
+	if err != nil {
+		logging.Error("ScanSecrets: %v", err)
+		return err
+	}
+
+	ps.Secrets = nil
+	for _, f := range ps.Files {
+		ps.Secrets = append(ps.Secrets, ps.scanFileSecrets(f, allow)...)
+	}
+	logging.Info("ScanSecrets: %d findings in %d files", len(ps.Secrets), len(ps.Files))
+	return nil
+}
+
+// loadSecretsAllowlist загружает allowlist из allowlistPath или, если он
+// пуст, из secrets.AllowlistFile в root. Отсутствие файла — не ошибка.
+func loadSecretsAllowlist(root, allowlistPath string) (*secrets.Allowlist, error) {
+	if allowlistPath == "" && root != "" {
+		candidate := filepath.Join(root, secrets.AllowlistFile)
+		if _, err := os.Stat(candidate); err == nil {
+			allowlistPath = candidate
+		}
+	}
+	if allowlistPath == "" {
+		return nil, nil
+	}
+	return secrets.LoadAllowlist(allowlistPath)
+}
+
+// scanFileSecrets возвращает находки в одном файле, не разрешённые allow.
+// Бинарные файлы пропускаются, кроме файлов с учётными данными.
+func (ps ProjectStats) scanFileSecrets(f FileStats, allow *secrets.Allowlist) []secrets.Finding {
+	switch f.Category {
+	case "image", "video", "audio", "archive", "font", "binary", "database":
+		if !secrets.IsCredentialFile(f.Path) {
+			return nil
+		}
+	}
+	findings, err := secrets.ScanFile(f.Path)
+	if err != nil {
+		logging.Warn("ScanSecrets: cannot scan %s: %v", f.Path, err)
+	}
+	var result []secrets.Finding
+	for _, finding := range findings {
+		if !allow.Allows(ps.RelPath(f.Path), finding.RuleID) {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rfxxfy/LintVision/coverage"
+	"github.com/rfxxfy/LintVision/deps"
+	"github.com/rfxxfy/LintVision/external"
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/rfxxfy/LintVision/lint"
+	"github.com/rfxxfy/LintVision/logging"
+	"github.com/rfxxfy/LintVision/secrets"
+)
+
+// Типы записей JSON Lines.
+const (
+	RecordFile    = "file"
+	RecordSummary = "summary"
+)
+
+// jsonlFile — запись JSON Lines с результатом по одному файлу.
+type jsonlFile struct {
+	Type string `json:"type"`
+	FileStats
+}
+
+// jsonlSummary — итоговая запись JSON Lines: сводные поля ProjectStats
+// и итоги по языкам, без списка файлов.
+type jsonlSummary struct {
+	Type string `json:"type"`
+	ProjectStats
+	// Files перекрывает поле ProjectStats, чтобы не повторять файлы.
+	Files     []FileStats           `json:"files,omitempty"`
+	Languages map[string]LineTotals `json:"languages"`
+}
+
+// WriteJSONL пишет готовый ProjectStats в формате JSON Lines:
+// по записи на файл и итоговую запись в конце.
+func WriteJSONL(w io.Writer, stats ProjectStats) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	languages := make(map[string]LineTotals)
+	stats.SchemaVersion = SchemaVersion
+	for _, f := range stats.Files {
+		if err := enc.Encode(jsonlFile{Type: RecordFile, FileStats: f}); err != nil {
+			return err
+		}
+		addLanguage(languages, f)
+	}
+	if err := enc.Encode(jsonlSummary{Type: RecordSummary, ProjectStats: stats, Languages: languages}); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// StreamJSONL анализирует root и пишет в w запись по каждому файлу сразу
+// после его обработки, а в конце — итоговую запись. Результаты совпадают
+// с Analyze, но файлы не хранятся в памяти, поэтому:
+//   - в возвращаемом ProjectStats поле Files пусто;
+//   - записи файлов с находками crlf выводятся после остальных, когда
+//     известно, принят ли в проекте CRLF;
+//   - история изменений и авторство (Options.History, Options.Blame)
+//     не поддерживаются и приводят к ошибке.
+func StreamJSONL(root string, w io.Writer, opts Options) (ProjectStats, error) {
+	if opts.History || opts.Blame {
+		return ProjectStats{}, fmt.Errorf("git history and blame are not supported with streaming output")
+	}
+	started := time.Now()
+	ps := ProjectStats{SchemaVersion: SchemaVersion, Root: root}
+	owners, report := loadCodeOwners(root)
+	ps.CodeOwners = report
+	ps.resetTotals()
+
+	var allow *secrets.Allowlist
+	if opts.Secrets {
+		var err error
+		if allow, err = loadSecretsAllowlist(root, opts.SecretsAllowlist); err != nil {
+			logging.Error("StreamJSONL: %v", err)
+			return ps, err
+		}
+	}
+
+	extra, err := loadOverlays(ps, opts)
+	if err != nil {
+		logging.Error("StreamJSONL: %v", err)
+		return ps, err
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	languages := make(map[string]LineTotals)
+	gateCheck := newGateEvaluator(gates.Current)
+	var manifests []string
+	// Для baseline хватает метрик файлов, поэтому хранятся только они.
+	_, useBaseline := baselinePath(root, opts.Baseline, opts.UpdateBaseline)
+	var baselineFiles []FileStats
+	// licenseFiles — поля файлов, нужные для отчёта о лицензиях.
+	var licenseFiles []FileStats
+	// Файлы с находками crlf выводятся после обхода, когда известно,
+	// принят ли в проекте CRLF (см. dropCRLFInCRLFRepo).
+	var endings lineEndings
+	var crlfFiles []FileStats
+
+	emit := func(f FileStats) error {
+		if deps.IsManifest(f.Path) {
+			manifests = append(manifests, f.Path)
+		}
+		if opts.Secrets {
+			ps.Secrets = append(ps.Secrets, ps.scanFileSecrets(f, allow)...)
+		}
+		if useBaseline {
+			baselineFiles = append(baselineFiles, FileStats{
+				Path: f.Path, LinesTotal: f.LinesTotal, LinesCode: f.LinesCode,
+				LinesComments: f.LinesComments, Size: f.Size, Hash: f.Hash,
+			})
+		}
+		ps.addTotals(f)
+		addLanguage(languages, f)
+		gateCheck.add(ps, f)
+		return enc.Encode(jsonlFile{Type: RecordFile, FileStats: f})
+	}
+
+	hf, hd, nhd, err := walkDir(root, func(path string) error {
+		f, err := ps.computeFile(path)
+		if err != nil {
+			logging.Error("StreamJSONL: error computing %s: %v", path, err)
+			return err
+		}
+		rel := ps.RelPath(f.Path)
+		f.Issues = takeIssues(extra.issues, f.Path, rel)
+		f.Coverage = extra.coverage[rel]
+		if owners != nil {
+			f.Owners = owners.Owners(rel)
+		}
+		endings.add(f)
+		if opts.ExcludeGenerated && (f.Generated || f.Vendored) {
+			return nil
+		}
+		if f.Category == "code" || license.IsLicenseFile(f.Path) {
+			licenseFiles = append(licenseFiles, FileStats{
+				Path: f.Path, Category: f.Category, Generated: f.Generated, Vendored: f.Vendored,
+				License: f.License, LicenseMatch: f.LicenseMatch,
+			})
+		}
+		if hasLintRule(f, lint.RuleCRLF) {
+			crlfFiles = append(crlfFiles, f)
+			return nil
+		}
+		return emit(f)
+	})
+	if err != nil {
+		return ps, err
+	}
+	convention := endings.crlfConvention()
+	for _, f := range crlfFiles {
+		if convention {
+			dropLintRule(&f, lint.RuleCRLF)
+		}
+		if err := emit(f); err != nil {
+			return ps, err
+		}
+	}
+	if convention {
+		logging.Info("StreamJSONL: CRLF is the project convention, crlf findings dropped")
+	}
+
+	ps.HiddenFiles = hf
+	ps.HiddenDirs = hd
+	ps.NonHiddenDirs = nhd
+	ps.Dependencies = deps.Collect(manifests)
+	ps.UnmatchedIssues = extra.unmatched
+	ps.Violations = gateCheck.finish()
+	ps.Licenses = ProjectStats{Root: root, Files: licenseFiles}.licenseReport()
+	if useBaseline {
+		snapshot := ps
+		snapshot.Files = baselineFiles
+		if err := applyBaseline(&snapshot, opts); err != nil {
+			logging.Error("StreamJSONL: baseline failed: %v", err)
+			return ps, err
+		}
+		ps.Violations = snapshot.Violations
+	}
+	ps.Metadata = newMetadata(root, opts, started)
+	if err := enc.Encode(jsonlSummary{Type: RecordSummary, ProjectStats: ps, Languages: languages}); err != nil {
+		return ps, err
+	}
+	logging.Info("StreamJSONL: streamed %d files", ps.Totals.Files)
+	return ps, bw.Flush()
+}
+
+// overlays — данные из внешних отчётов, привязанные к путям файлов
+// относительно корня.
+type overlays struct {
+	issues    map[string][]external.Finding
+	unmatched []external.Finding
+	coverage  map[string]*Coverage
+}
+
+// loadOverlays загружает отчёты внешних линтеров и файлы покрытия для
+// потокового анализа. Чтобы сопоставить пути из отчётов файлам проекта,
+// директория предварительно обходится без чтения файлов.
+func loadOverlays(ps ProjectStats, opts Options) (overlays, error) {
+	var o overlays
+	if len(opts.Import) == 0 && len(opts.Coverage) == 0 {
+		return o, nil
+	}
+	var rels []string
+	if _, _, _, err := walkDir(ps.Root, func(path string) error {
+		rels = append(rels, ps.RelPath(path))
+		return nil
+	}); err != nil {
+		return o, err
+	}
+	m := newFileMatcher(ps.Root, rels)
+
+	findings, err := external.Collect(opts.Import)
+	if err != nil {
+		return o, err
+	}
+	o.issues, o.unmatched = matchIssues(m, findings)
+
+	profiles, err := coverage.Collect(opts.Coverage)
+	if err != nil {
+		return o, err
+	}
+	o.coverage, _ = matchCoverage(m, profiles)
+	return o, nil
+}
+
+func addLanguage(languages map[string]LineTotals, f FileStats) {
+	if f.Language == "" {
+		return
+	}
+	t := languages[f.Language]
+	t.add(f)
+	languages[f.Language] = t
+}
@@ -0,0 +1,73 @@
+package stats
+
+import "sort"
+
+type LanguageRow struct {
+	Name string `json:"name"`
+	LineTotals
+}
+
+type CategoryRow struct {
+	Name  string `json:"name"`
+	Files int    `json:"files"`
+}
+
+// Summary — сводка по проекту, общая для текстового вывода GUI
+// и Markdown-отчёта.
+type Summary struct {
+	Totals     LineTotals
+	Languages  []LanguageRow
+	Categories []CategoryRow
+	Largest    []FileStats
+}
+
+// Summarize группирует статистику по языкам и категориям и выбирает
+// topN крупнейших файлов по числу строк (topN <= 0 — без списка).
+func Summarize(stats ProjectStats, topN int) Summary {
+	s := Summary{}
+	byLanguage := make(map[string]*LineTotals)
+	for _, f := range stats.Files {
+		s.Totals.add(f)
+		if f.Language == "" {
+			continue
+		}
+		t, ok := byLanguage[f.Language]
+		if !ok {
+			t = &LineTotals{}
+			byLanguage[f.Language] = t
+		}
+		t.add(f)
+	}
+
+	for name, t := range byLanguage {
+		s.Languages = append(s.Languages, LanguageRow{Name: name, LineTotals: *t})
+	}
+	sort.Slice(s.Languages, func(i, j int) bool {
+		if s.Languages[i].Code != s.Languages[j].Code {
+			return s.Languages[i].Code > s.Languages[j].Code
+		}
+		return s.Languages[i].Name < s.Languages[j].Name
+	})
+
+	for name, files := range stats.CategoryCounts {
+		s.Categories = append(s.Categories, CategoryRow{Name: name, Files: files})
+	}
+	sort.Slice(s.Categories, func(i, j int) bool {
+		if s.Categories[i].Files != s.Categories[j].Files {
+			return s.Categories[i].Files > s.Categories[j].Files
+		}
+		return s.Categories[i].Name < s.Categories[j].Name
+	})
+
+	if topN > 0 {
+		largest := append([]FileStats(nil), stats.Files...)
+		sort.SliceStable(largest, func(i, j int) bool {
+			return largest[i].LinesTotal > largest[j].LinesTotal
+		})
+		if len(largest) > topN {
+			largest = largest[:topN]
+		}
+		s.Largest = largest
+	}
+	return s
+}
@@ -0,0 +1,133 @@
+package stats_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func ratchetConfig() gates.Config {
+	maxLines := 500
+	maxDup := 10.0
+	return gates.Config{
+		MaxLinesPerFile:       &maxLines,
+		MinCommentRatio:       map[string]float64{"Go": 0.1},
+		MaxDuplicationPercent: &maxDup,
+	}
+}
+
+func rules(violations []gates.Violation) []string {
+	var result []string
+	for _, v := range violations {
+		subject := v.Path
+		if subject == "" {
+			subject = v.Language
+		}
+		result = append(result, v.Rule+" "+subject)
+	}
+	return result
+}
+
+func TestBaseline_Regressions(t *testing.T) {
+	t.Parallel()
+	old := gateStats()
+	stats.EvaluateGates(&old, ratchetConfig())
+	baseline := stats.NewBaseline(old)
+	assert.Len(t, baseline.Files, 4)
+
+	unchanged := gateStats()
+	stats.EvaluateGates(&unchanged, ratchetConfig())
+	assert.NotEmpty(t, unchanged.Violations)
+	assert.Empty(t, baseline.Regressions(unchanged))
+
+	worse := gateStats()
+	worse.Files[0].LinesTotal = 650
+	worse.Files[0].LinesComments = 0
+	worse.Files = append(worse.Files,
+		stats.FileStats{Path: "/work/app/new.go", Category: "code", Language: "Go", Hash: "n1",
+			LinesTotal: 501, LinesCode: 501},
+		stats.FileStats{Path: "/work/app/lib_copy.py", Category: "code", Language: "Python", Hash: "h2",
+			LinesTotal: 10, LinesCode: 5, LinesComments: 5},
+	)
+	stats.EvaluateGates(&worse, ratchetConfig())
+	assert.ElementsMatch(t, []string{
+		gates.RuleMaxLinesPerFile + " big.go",
+		gates.RuleMaxLinesPerFile + " new.go",
+		gates.RuleMinCommentRatio + " Go",
+		gates.RuleMaxDuplicationPercent + " ",
+		gates.RuleMaxDuplicationPercent + " lib.py",
+		gates.RuleMaxDuplicationPercent + " lib_copy.py",
+	}, rules(baseline.Regressions(worse)))
+}
+
+func TestAnalyze_BaselineRatchet(t *testing.T) {
+	saved := gates.Current
+	t.Cleanup(func() { gates.Current = saved })
+	maxLines := 2
+	gates.Current = gates.Config{MaxLinesPerFile: &maxLines}
+
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+
+	ps, err := stats.Analyze(root, stats.Options{})
+	assert.NoError(t, err)
+	assert.Len(t, ps.Violations, 1)
+
+	ps, err = stats.Analyze(root, stats.Options{UpdateBaseline: true})
+	assert.NoError(t, err)
+	assert.Empty(t, ps.Violations)
+	_, err = os.Stat(filepath.Join(root, stats.BaselineFile))
+	assert.NoError(t, err)
+
+	ps, err = stats.Analyze(root, stats.Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, ps.Violations)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {\n}\n"), 0o644))
+	ps, err = stats.Analyze(root, stats.Options{})
+	assert.NoError(t, err)
+	assert.Len(t, ps.Violations, 1)
+}
+
+func TestStreamJSONL_Baseline(t *testing.T) {
+	saved := gates.Current
+	t.Cleanup(func() { gates.Current = saved })
+	maxLines := 2
+	gates.Current = gates.Config{MaxLinesPerFile: &maxLines}
+
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+
+	ps, err := stats.StreamJSONL(root, io.Discard, stats.Options{UpdateBaseline: true})
+	assert.NoError(t, err)
+	assert.Empty(t, ps.Violations)
+	analyzed, err := stats.Analyze(root, stats.Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, analyzed.Violations, "a baseline written while streaming is usable by Analyze")
+
+	ps, err = stats.StreamJSONL(root, io.Discard, stats.Options{})
+	assert.NoError(t, err)
+	assert.Empty(t, ps.Violations, "the default baseline is applied to streamed violations")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {\n}\n"), 0o644))
+	ps, err = stats.StreamJSONL(root, io.Discard, stats.Options{})
+	assert.NoError(t, err)
+	assert.Len(t, ps.Violations, 1)
+}
+
+func TestLoadBaseline_Errors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	_, err := stats.LoadBaseline(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	newer := filepath.Join(dir, "newer.json")
+	assert.NoError(t, os.WriteFile(newer, []byte(`{"version": 99}`), 0o644))
+	_, err = stats.LoadBaseline(newer)
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package stats_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOwners(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		".github/CODEOWNERS": "/api/ @org/backend\n*.js @org/frontend\n/api/gen/\n",
+		"api/server.go":      "package api\n\nfunc Serve() {}\n",
+		"api/gen/types.go":   "package gen\n",
+		"web/app.js":         "let x = 1;\n",
+		"api/client.js":      "let c = 1;\nlet d = 2;\n",
+		"main.go":            "package main\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	owners := make(map[string][]string)
+	for _, f := range ps.Files {
+		owners[ps.RelPath(f.Path)] = f.Owners
+	}
+	assert.Equal(t, []string{"@org/backend"}, owners["api/server.go"])
+	assert.Equal(t, []string{"@org/frontend"}, owners["api/client.js"], "the last matching rule wins")
+	assert.Nil(t, owners["api/gen/types.go"], "a rule without owners makes files unowned")
+
+	if !assert.NotNil(t, ps.CodeOwners) {
+		return
+	}
+	assert.Equal(t, ".github/CODEOWNERS", ps.CodeOwners.File)
+	assert.Equal(t, map[string]stats.LineTotals{
+		"@org/backend":  {Files: 1, Lines: 3, Code: 2, Blank: 1},
+		"@org/frontend": {Files: 2, Lines: 3, Code: 3},
+	}, ps.CodeOwners.ByOwner)
+	assert.ElementsMatch(t, []string{".github/CODEOWNERS", "api/gen/types.go", "main.go"}, ps.CodeOwners.Unowned)
+	assert.Equal(t, 3, ps.CodeOwners.UnownedTotals.Files)
+
+	var buf bytes.Buffer
+	_, err = stats.StreamJSONL(root, &buf, stats.Options{})
+	assert.NoError(t, err)
+	var summary stats.ProjectStats
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		summary = stats.ProjectStats{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &summary))
+	}
+	if assert.NotNil(t, summary.CodeOwners, "streaming reports owners too") {
+		assert.Equal(t, ps.CodeOwners.ByOwner, summary.CodeOwners.ByOwner)
+	}
+}
+
+func TestCodeOwners_IgnoresGitMetadata(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		".github/CODEOWNERS":         "* @org/core\n/src/ @team\n",
+		"src/main.go":                "package main\n",
+		"README.md":                  "# app\n",
+		".git/HEAD":                  "ref: refs/heads/main\n",
+		".git/config":                "[core]\n",
+		".git/hooks/pre-push.sample": "#!/bin/sh\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) || !assert.NotNil(t, ps.CodeOwners) {
+		return
+	}
+	assert.Equal(t, 2, ps.CodeOwners.ByOwner["@org/core"].Files, "README.md and CODEOWNERS, not git internals")
+	assert.Equal(t, 1, ps.CodeOwners.ByOwner["@team"].Files)
+	assert.Empty(t, ps.CodeOwners.Unowned)
+}
+
+func TestCodeOwners_Absent(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{"main.go": "package main\n"})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	assert.NoError(t, err)
+	assert.Nil(t, ps.CodeOwners)
+	assert.Nil(t, ps.Files[0].Owners)
+}
@@ -3,6 +3,7 @@ package stats_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rfxxfy/LintVision/stats"
@@ -148,3 +149,308 @@ func TestComputeProjectStats(t *testing.T) {
 	}
 	assert.Equal(t, wantCategories, got.CategoryCounts)
 }
+
+const mitText = "Permission is hereby granted, free of charge, to any person obtaining a copy " +
+	"of this software and associated documentation files (the \"Software\"), to deal " +
+	"in the Software without restriction, including without limitation the rights " +
+	"to use, copy, modify, merge, publish, distribute, sublicense, and/or sell " +
+	"copies of the Software, and to permit persons to whom the Software is " +
+	"furnished to do so, subject to the following conditions:\n" +
+	"The above copyright notice and this permission notice shall be included in all " +
+	"copies or substantial portions of the Software.\n" +
+	"THE SOFTWARE IS PROVIDED \"AS IS\", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR " +
+	"IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, " +
+	"FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE " +
+	"AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER " +
+	"LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, " +
+	"OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE " +
+	"SOFTWARE.\n"
+
+func TestComputeProjectStats_Licenses(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	paths := []string{
+		createTempFile(t, tmpDir, "LICENSE", mitText),
+		createTempFile(t, tmpDir, "ok.go", "// SPDX-License-Identifier: MIT\npackage main\n"),
+		createTempFile(t, tmpDir, "gpl.go", "// SPDX-License-Identifier: GPL-3.0-only\npackage main\n"),
+		createTempFile(t, tmpDir, "bare.go", "package main\n"),
+	}
+
+	got, err := stats.ComputeProjectStats(paths)
+	assert.NoError(t, err)
+	if assert.NotNil(t, got.Licenses) {
+		assert.Equal(t, "MIT", got.Licenses.Project)
+		assert.Equal(t, []string{paths[3]}, got.Licenses.MissingHeader)
+		if assert.Len(t, got.Licenses.Conflicts, 1) {
+			assert.Equal(t, paths[2], got.Licenses.Conflicts[0].Path)
+			assert.Equal(t, "GPL-3.0-only", got.Licenses.Conflicts[0].License)
+		}
+	}
+}
+
+func TestComputeProjectStatsFromDir_LicenseSurvivesTreeRemoval(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"LICENSE":           mitText,
+		"main.go":           "package main\n",
+		"vendor/lib/lib.go": "package lib\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, f := range ps.Files {
+		if ps.RelPath(f.Path) == "LICENSE" && assert.NotNil(t, f.LicenseMatch) {
+			assert.Equal(t, "MIT", f.LicenseMatch.SPDX)
+		}
+	}
+	assert.NoError(t, os.RemoveAll(root))
+
+	ps.DropGenerated()
+	if assert.NotNil(t, ps.Licenses) {
+		assert.Equal(t, "MIT", ps.Licenses.Project, "re-aggregation does not read the removed tree")
+	}
+}
+
+func corpusText(t *testing.T, id string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "license", "corpus", id+".txt"))
+	if err != nil {
+		t.Fatalf("cannot read corpus text %s: %v", id, err)
+	}
+	return string(data)
+}
+
+func TestComputeProjectStatsFromDir_DualLicense(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"LICENSE-APACHE": corpusText(t, "Apache-2.0"),
+		"LICENSE-MIT":    mitText,
+		"lib.go":         "// SPDX-License-Identifier: MIT\npackage lib\n",
+		"gpl.go":         "// SPDX-License-Identifier: GPL-3.0-only\npackage lib\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) || !assert.NotNil(t, ps.Licenses) {
+		return
+	}
+	assert.Equal(t, "Apache-2.0 OR MIT", ps.Licenses.Project)
+	if assert.Len(t, ps.Licenses.Conflicts, 1, "root license files do not conflict with each other") {
+		assert.Equal(t, "gpl.go", ps.RelPath(ps.Licenses.Conflicts[0].Path))
+	}
+}
+
+func TestComputeProjectStatsFromDir_CopyleftLicense(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"COPYING": corpusText(t, "GPL-3.0-only"),
+		"main.c":  "// SPDX-License-Identifier: GPL-3.0-or-later\nint main() { return 0; }\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) || !assert.NotNil(t, ps.Licenses) {
+		return
+	}
+	assert.Equal(t, "GPL-3.0-only", ps.Licenses.Project)
+	assert.Empty(t, ps.Licenses.Conflicts, "-or-later headers match the GPL-3.0 text")
+
+	createTestTree(t, root, map[string]string{"COPYING.LESSER": corpusText(t, "LGPL-3.0-only")})
+	ps, err = stats.ComputeProjectStatsFromDir(root)
+	if assert.NoError(t, err) && assert.NotNil(t, ps.Licenses) {
+		assert.Equal(t, "LGPL-3.0-only", ps.Licenses.Project)
+	}
+}
+
+func TestComputeProjectStatsFromDir_ProjectLicenseFromRoot(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"testdata/LICENSE":   mitText,
+		"vendor/lib/LICENSE": mitText,
+		"vendor/lib/lib.go":  "package lib\n",
+		"main.go":            "// SPDX-License-Identifier: Apache-2.0\npackage main\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) || !assert.NotNil(t, ps.Licenses) {
+		return
+	}
+	assert.Empty(t, ps.Licenses.Project, "nested license files do not define the project license")
+	assert.Empty(t, ps.Licenses.Conflicts)
+	assert.Empty(t, ps.Licenses.MissingHeader, "vendored code is not checked for headers")
+	if assert.Len(t, ps.Licenses.LicenseFiles, 1) {
+		assert.Equal(t, "testdata/LICENSE", ps.RelPath(ps.Licenses.LicenseFiles[0].Path))
+	}
+
+	createTestTree(t, root, map[string]string{"LICENSE": mitText})
+	ps, err = stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) || !assert.NotNil(t, ps.Licenses) {
+		return
+	}
+	assert.Equal(t, "MIT", ps.Licenses.Project)
+	if assert.Len(t, ps.Licenses.Conflicts, 1) {
+		assert.Equal(t, "main.go", ps.RelPath(ps.Licenses.Conflicts[0].Path))
+	}
+}
+
+func TestComputeFileStats_SPDXOnlyInComments(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	code := createTempFile(t, dir, "detect.go", "package license\n\nconst spdxTag = \"SPDX-License-Identifier:\"\n")
+	got, err := stats.ComputeFileStats(code)
+	assert.NoError(t, err)
+	assert.Empty(t, got.License, "a tag inside a string literal is not a license header")
+
+	script := createTempFile(t, dir, "run.py", "#!/usr/bin/env python3\n# SPDX-License-Identifier: Apache-2.0\n")
+	got, err = stats.ComputeFileStats(script)
+	assert.NoError(t, err)
+	assert.Equal(t, "Apache-2.0", got.License)
+}
+
+func TestComputeFileStats_Markers(t *testing.T) {
+	t.Parallel()
+	path := createTempFile(t, t.TempDir(), "main.go", `package main
+
+// TODO(bob): split this function
+func main() {
+	s := "TODO: not a comment"
+	_ = s // FIXME #7
+}
+`)
+	got, err := stats.ComputeFileStats(path)
+	assert.NoError(t, err)
+	if assert.Len(t, got.Markers, 2) {
+		assert.Equal(t, "TODO", got.Markers[0].Kind)
+		assert.Equal(t, 3, got.Markers[0].Line)
+		assert.Equal(t, "bob", got.Markers[0].Owner)
+		assert.Equal(t, "FIXME", got.Markers[1].Kind)
+		assert.Equal(t, 6, got.Markers[1].Line)
+		assert.Equal(t, "#7", got.Markers[1].Issue)
+	}
+
+	ps, err := stats.ComputeProjectStats([]string{path})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"TODO": 1, "FIXME": 1}, ps.MarkerCounts)
+}
+
+func TestComputeFileStats_MarkersOutsideComments(t *testing.T) {
+	t.Parallel()
+	path := createTempFile(t, t.TempDir(), "main.go", `package main
+
+// This is not a TODO list.
+var s = "// TODO: inside a string"
+var u = "a \" // TODO: still a string"
+var v = "//" // HACK: after the string
+`)
+	got, err := stats.ComputeFileStats(path)
+	assert.NoError(t, err)
+	if assert.Len(t, got.Markers, 1) {
+		assert.Equal(t, "HACK", got.Markers[0].Kind)
+		assert.Equal(t, 6, got.Markers[0].Line)
+		assert.Equal(t, "after the string", got.Markers[0].Text)
+	}
+	assert.Equal(t, 2, got.LinesComments)
+}
+
+func TestComputeProjectStats_GeneratedAndVendored(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	paths := []string{
+		createTempFile(t, tmpDir, "main.go", "package main\n\nfunc main() {}\n"),
+		createTempFile(t, tmpDir, "api.go", "// Code generated by mockgen. DO NOT EDIT.\npackage main\n"),
+		createTempFile(t, tmpDir, "vendor/lib/lib.go", "package lib\n"),
+		createTempFile(t, tmpDir, "app.js", strings.Repeat("x", 2000)+"\n"),
+	}
+
+	ps, err := stats.ComputeProjectStats(paths)
+	assert.NoError(t, err)
+	assert.False(t, ps.Files[0].Generated)
+	assert.True(t, ps.Files[1].Generated)
+	assert.True(t, ps.Files[2].Vendored)
+	assert.True(t, ps.Files[3].Generated, "minified file")
+
+	assert.Equal(t, 4, ps.Totals.Files)
+	assert.Equal(t, 5, ps.Totals.Code)
+	assert.Equal(t, 2, ps.GeneratedTotals.Files)
+	assert.Equal(t, 1, ps.VendoredTotals.Code)
+	assert.Equal(t, stats.LineTotals{Files: 1, Lines: 3, Code: 2, Blank: 1}, ps.TotalsExcluding(true, true))
+
+	ps.DropGenerated()
+	assert.Len(t, ps.Files, 1)
+	assert.Equal(t, map[string]int{"code": 1}, ps.CategoryCounts)
+	assert.Equal(t, 2, ps.Totals.Code)
+}
+
+func TestComputeProjectStatsFromDir_Tests(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	createTestTree(t, tmpDir, map[string]string{
+		"api/handler.go":      "package api\n\nfunc A() {}\nfunc B() {}\n",
+		"api/handler_test.go": "package api\n\nfunc TestA() {}\n",
+		"worker/job.py":       "def run():\n    pass\n",
+		"worker/test_job.py":  "def test_run():\n    assert True\n    assert True\n    assert True\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, ps.TestTotals.Code)
+	assert.Equal(t, 5, ps.NonTestTotals.Code)
+	assert.Equal(t, stats.TestRatio{TestCode: 2, Code: 3, Ratio: 2.0 / 3.0}, ps.TestRatioByLanguage["Go"])
+	assert.Equal(t, stats.TestRatio{TestCode: 4, Code: 2, Ratio: 2}, ps.TestRatioByDir["worker"])
+}
+
+func TestComputeFileStats_Lint(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	path := createTempFile(t, tmpDir, "app.js", "let a = 1;  \r\n\r\nlet b = 2;")
+
+	fs, err := stats.ComputeFileStats(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fs.LinesTotal)
+	assert.Equal(t, 2, fs.LinesCode)
+	assert.Equal(t, 1, fs.LinesBlank)
+
+	var rules []string
+	for _, f := range fs.Lint {
+		assert.Equal(t, path, f.Path)
+		rules = append(rules, f.Rule)
+	}
+	assert.Equal(t, []string{"trailing-whitespace", "crlf", "final-newline"}, rules)
+	assert.Equal(t, 11, fs.Lint[0].Column)
+}
+
+func TestComputeFileStats_LintSuppressions(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	path := createTempFile(t, tmpDir, "app.py", "# lintvision:ignore-file crlf\nx = 1  # lintvision:ignore  \n")
+
+	fs, err := stats.ComputeFileStats(path)
+	assert.NoError(t, err)
+	if assert.Len(t, fs.Lint, 1) {
+		assert.Equal(t, "unused-suppression", fs.Lint[0].Rule)
+		assert.Equal(t, 1, fs.Lint[0].Line)
+	}
+}
+
+func TestComputeProjectStats_LintCRLFRepo(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	crlfRepo := []string{
+		createTempFile(t, tmpDir, "a.cs", "class A {}\r\n// a\r\n"),
+		createTempFile(t, tmpDir, "b.cs", "class B {}\r\n// b\r\n"),
+		createTempFile(t, tmpDir, "c.cs", "class C {}\n// c\n"),
+		createTempFile(t, tmpDir, "vendor/d.cs", "class D {}  \n"),
+	}
+	ps, err := stats.ComputeProjectStatsFromDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, ps.LintCounts)
+
+	lfRepo := []string{crlfRepo[0], crlfRepo[2], createTempFile(t, tmpDir, "e.cs", "class E {}\n// e\n")}
+	ps, err = stats.ComputeProjectStats(lfRepo)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"crlf": 1}, ps.LintCounts)
+}
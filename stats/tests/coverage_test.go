@@ -0,0 +1,78 @@
+package stats_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyCoverage(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"go.mod":       "module github.com/user/app // app\n\ngo 1.22\n",
+		"pkg/a.go":     "package pkg\n\nfunc A() {\n\tprintln()\n}\n",
+		"web/app.js":   "let x = 1;\nlet y = 2;\n",
+		"pkg/b/a.go":   "package b\n",
+		"docs/note.md": "# note\n",
+	})
+	profiles := t.TempDir()
+	goProfile := filepath.Join(profiles, "coverage.out")
+	assert.NoError(t, os.WriteFile(goProfile, []byte("mode: set\n"+
+		"github.com/user/app/pkg/a.go:3.10,5.2 1 1\n"+
+		"github.com/user/app/pkg/b/a.go:1.1,1.9 1 0\n"+
+		"github.com/user/app/gone.go:1.1,2.1 1 0\n"), 0o644))
+	lcov := filepath.Join(profiles, "lcov.info")
+	assert.NoError(t, os.WriteFile(lcov, []byte("SF:/ci/checkout/web/app.js\nDA:1,1\nDA:2,0\nend_of_record\n"), 0o644))
+
+	ps, err := stats.Analyze(root, stats.Options{Coverage: []string{goProfile, lcov}})
+	assert.NoError(t, err)
+
+	byPath := make(map[string]*stats.Coverage)
+	for _, f := range ps.Files {
+		byPath[ps.RelPath(f.Path)] = f.Coverage
+	}
+	assert.Equal(t, &stats.Coverage{Covered: 3, Percent: 100}, byPath["pkg/a.go"])
+	assert.Equal(t, &stats.Coverage{Uncovered: 1}, byPath["pkg/b/a.go"])
+	assert.Equal(t, &stats.Coverage{Covered: 1, Uncovered: 1, Percent: 50}, byPath["web/app.js"])
+	assert.Nil(t, byPath["docs/note.md"])
+
+	assert.Equal(t, &stats.Coverage{Covered: 4, Uncovered: 2, Percent: 4 * 100.0 / 6}, ps.CoverageTotals)
+	assert.Equal(t, stats.Coverage{Covered: 3, Uncovered: 1, Percent: 75}, ps.CoverageByLanguage["Go"])
+	assert.Equal(t, stats.Coverage{Covered: 1, Uncovered: 1, Percent: 50}, ps.CoverageByDir["web"])
+
+	untested := stats.UntestedFiles(ps, 1)
+	if assert.Len(t, untested, 1) {
+		assert.Equal(t, "web/app.js", ps.RelPath(untested[0].Path))
+	}
+
+	var buf bytes.Buffer
+	_, err = stats.StreamJSONL(root, &buf, stats.Options{Coverage: []string{lcov}})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"coverage":{"covered":1,"uncovered":1,"percent":50}`)
+}
+
+func TestApplyCoverage_NoBasenameMatch(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"pkg/util.go": "package pkg\n",
+		"web/app.js":  "let x = 1;\n",
+	})
+	lcov := filepath.Join(t.TempDir(), "lcov.info")
+	assert.NoError(t, os.WriteFile(lcov, []byte(
+		"SF:cmd/x/util.go\nDA:1,0\nend_of_record\n"+
+			"SF:github.com/user/other/pkg/util.go\nDA:1,0\nend_of_record\n"+
+			"SF:/ci/other/app.js\nDA:1,0\nend_of_record\n"), 0o644))
+
+	ps, err := stats.Analyze(root, stats.Options{Coverage: []string{lcov}})
+	assert.NoError(t, err)
+	for _, f := range ps.Files {
+		assert.Nil(t, f.Coverage, "%s must not take coverage of another file with the same name", ps.RelPath(f.Path))
+	}
+	assert.Nil(t, ps.CoverageTotals)
+}
@@ -0,0 +1,106 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func diffPair() (stats.ProjectStats, stats.ProjectStats) {
+	oldStats := stats.ProjectStats{
+		Root: "/old/app",
+		Files: []stats.FileStats{
+			{Path: "/old/app/main.go", Category: "code", Language: "Go", Hash: "a1",
+				LinesTotal: 10, LinesCode: 8, LinesBlank: 2},
+			{Path: "/old/app/util/strings.go", Category: "code", Language: "Go", Hash: "b1",
+				LinesTotal: 5, LinesCode: 5},
+			{Path: "/old/app/legacy/old.py", Category: "code", Language: "Python", Hash: "c1",
+				LinesTotal: 7, LinesCode: 6, LinesComments: 1},
+			{Path: "/old/app/README.md", Category: "markup", Hash: "d1", LinesTotal: 3},
+		},
+	}
+	newStats := stats.ProjectStats{
+		Root: "/new/app",
+		Files: []stats.FileStats{
+			{Path: "/new/app/main.go", Category: "code", Language: "Go", Hash: "a2",
+				LinesTotal: 14, LinesCode: 11, LinesComments: 1, LinesBlank: 2},
+			{Path: "/new/app/pkg/text/strings.go", Category: "code", Language: "Go", Hash: "b1",
+				LinesTotal: 5, LinesCode: 5},
+			{Path: "/new/app/web/app.ts", Category: "code", Language: "TypeScript", Hash: "e1",
+				LinesTotal: 20, LinesCode: 18, LinesBlank: 2},
+			{Path: "/new/app/README.md", Category: "markup", Hash: "d1", LinesTotal: 3},
+		},
+	}
+	return oldStats, newStats
+}
+
+func TestDiffStats(t *testing.T) {
+	t.Parallel()
+	d := stats.DiffStats(diffPair())
+
+	assert.Equal(t, 1, d.Added)
+	assert.Equal(t, 1, d.Removed)
+	assert.Equal(t, 1, d.Modified)
+	assert.Equal(t, 1, d.Renamed)
+	assert.Equal(t, []stats.FileChange{
+		{Status: stats.FileRemoved, Path: "legacy/old.py", Language: "Python", Category: "code",
+			Delta: stats.LineTotals{Files: -1, Lines: -7, Code: -6, Comments: -1}},
+		{Status: stats.FileModified, Path: "main.go", Language: "Go", Category: "code",
+			Delta: stats.LineTotals{Lines: 4, Code: 3, Comments: 1}},
+		{Status: stats.FileRenamed, Path: "pkg/text/strings.go", OldPath: "util/strings.go", Language: "Go", Category: "code"},
+		{Status: stats.FileAdded, Path: "web/app.ts", Language: "TypeScript", Category: "code",
+			Delta: stats.LineTotals{Files: 1, Lines: 20, Code: 18, Blank: 2}},
+	}, d.Files)
+
+	assert.Equal(t, stats.LineTotals{Lines: 17, Code: 15, Blank: 2}, d.Totals.Delta)
+	if assert.Len(t, d.Languages, 3) {
+		assert.Equal(t, "TypeScript", d.Languages[0].Name)
+		assert.Equal(t, "Python", d.Languages[1].Name)
+		assert.Equal(t, "Go", d.Languages[2].Name)
+		assert.Equal(t, 3, d.Languages[2].Delta.Code)
+	}
+	assert.Len(t, d.Categories, 1)
+
+	dirs := make(map[string]stats.LineTotals)
+	for _, g := range d.Directories {
+		dirs[g.Name] = g.Delta
+	}
+	assert.Equal(t, -5, dirs["util"].Code)
+	assert.Equal(t, 5, dirs["pkg"].Code)
+	assert.Equal(t, 3, dirs["."].Code)
+}
+
+func TestDiffStats_Unchanged(t *testing.T) {
+	t.Parallel()
+	_, newStats := diffPair()
+	d := stats.DiffStats(newStats, newStats)
+	assert.False(t, d.Changed())
+	assert.Empty(t, d.Languages)
+}
+
+func TestWriteDiff_Formats(t *testing.T) {
+	t.Parallel()
+	d := stats.DiffStats(diffPair())
+
+	var text bytes.Buffer
+	assert.NoError(t, stats.WriteDiff(&text, d, ""))
+	assert.Contains(t, text.String(), "Файлы: +1 добавлено, -1 удалено, ~1 изменено, 1 переименовано")
+	assert.Contains(t, text.String(), "R  util/strings.go -> pkg/text/strings.go")
+	assert.Contains(t, text.String(), "TypeScript     +1     +20  +18")
+
+	var md bytes.Buffer
+	assert.NoError(t, stats.WriteDiff(&md, d, stats.FormatMarkdown))
+	assert.Contains(t, md.String(), "| **Итого** | 4 | 42 (▲ 17) | 34 (▲ 15) | 1 | 4 (▲ 2) |")
+	assert.Contains(t, md.String(), "| D | `legacy/old.py` | -7 | -6 |")
+
+	var js bytes.Buffer
+	assert.NoError(t, stats.WriteDiff(&js, d, stats.FormatJSON))
+	var decoded stats.StatsDiff
+	assert.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, d, decoded)
+
+	assert.Error(t, stats.WriteDiff(&bytes.Buffer{}, d, "xml"))
+}
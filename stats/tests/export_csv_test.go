@@ -0,0 +1,102 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rfxxfy/LintVision/external"
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/lint"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func csvStats() stats.ProjectStats {
+	return stats.ProjectStats{
+		Root: "/work/app",
+		Files: []stats.FileStats{
+			{Path: "/work/app/main.go", Ext: ".go", Category: "code", Language: "Go",
+				LinesTotal: 10, LinesCode: 8, LinesComments: 1, LinesBlank: 1, Size: 120, Test: false,
+				Lint:     []lint.Finding{{Rule: lint.RuleFinalNewline}},
+				Issues:   []external.Finding{{Rule: "errcheck"}, {Rule: "unused"}},
+				Coverage: &stats.Coverage{Covered: 2, Uncovered: 1, Percent: 66.666},
+				Owners:   []string{"@org/core", "@alice"},
+				History: &stats.FileHistory{Commits: 4, LinesAdded: 12, LinesRemoved: 2,
+					LastModified: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Hotspot: 0.8},
+				Authors: []history.AuthorLines{
+					{Name: "Alice", Email: "alice@example.com", Lines: 6},
+					{Name: "Bob", Email: "bob@example.com", Lines: 3},
+					{Name: "Carol", Email: "carol@example.com", Lines: 1},
+				}},
+			{Path: "/work/app/main_test.go", Ext: ".go", Category: "code", Language: "Go",
+				LinesTotal: 5, LinesCode: 4, LinesBlank: 1, Test: true},
+			{Path: "/work/app/README.md", Ext: ".md", Category: "markup", LinesTotal: 3, LinesBlank: 1},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteCSV(&buf, csvStats()))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	assert.Equal(t, "path", records[0][0])
+	assert.Equal(t, []string{"main.go", ".go", "code", "Go", "10", "8", "1", "1", "120", "", "", "false", "false", "false",
+		"0", "1", "2", "66.7", "@org/core @alice",
+		"4", "12", "2", "2026-03-01T12:00:00Z", "0.8", "3", "alice@example.com", "1"}, records[1])
+	assert.Equal(t, []string{"main_test.go", ".go", "code", "Go", "5", "4", "0", "1", "0", "", "", "false", "false", "true",
+		"0", "0", "0", "", "", "", "", "", "", "", "", "", ""}, records[2])
+	for _, rec := range records {
+		assert.Len(t, rec, len(records[0]))
+	}
+}
+
+func TestWriteCategoryTSV(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteCategoryTSV(&buf, csvStats()))
+	assert.Equal(t, "category\tfiles\tlines_total\tlines_code\tlines_comments\tlines_blank\n"+
+		"code\t2\t15\t12\t1\t2\n"+
+		"markup\t1\t3\t0\t0\t1\n", buf.String())
+}
+
+func TestSaveStatsAs_CSVWritesSummary(t *testing.T) {
+	t.Parallel()
+	outFile := filepath.Join(t.TempDir(), "report.csv")
+	assert.NoError(t, stats.SaveStatsAs(csvStats(), outFile, stats.FormatCSV))
+
+	_, err := os.Stat(outFile)
+	assert.NoError(t, err)
+	data, err := os.ReadFile(stats.CategorySummaryPath(outFile))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "code,2,15,12,1,2")
+}
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path   string
+		want   string
+		wantOk bool
+	}{
+		{"out.csv", stats.FormatCSV, true},
+		{"out.TSV", stats.FormatTSV, true},
+		{"out.json", stats.FormatJSON, true},
+		{"bom.cdx.json", stats.FormatCycloneDX, true},
+		{"bom.spdx", stats.FormatSPDX, true},
+		{"out.txt", stats.FormatTable, true},
+		{"out.xml", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := stats.FormatFromPath(tt.path)
+		assert.Equal(t, tt.wantOk, ok, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+}
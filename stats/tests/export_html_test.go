@@ -0,0 +1,36 @@
+package stats_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTML(t *testing.T) {
+	t.Parallel()
+	ps := csvStats()
+	ps.CategoryCounts = map[string]int{"code": 2, "markup": 1}
+	ps.Files = append(ps.Files, stats.FileStats{
+		Path: "/work/app/web/</script><b>x.go", Ext: ".go", Category: "code", Language: "Go",
+	})
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteHTML(&buf, ps))
+	out := buf.String()
+
+	assert.Contains(t, out, "<title>LintVision — app</title>")
+	assert.Contains(t, out, `"path":"main_test.go"`)
+	assert.Contains(t, out, `{"name":"code","files":2}`)
+	assert.NotContains(t, out, "</script><b>", "file paths must be escaped inside the inline script")
+	assert.NotContains(t, out, "<script src")
+	assert.NotContains(t, out, "<link")
+}
+
+func TestSaveStatsAs_HTMLByExtension(t *testing.T) {
+	t.Parallel()
+	format, ok := stats.FormatFromPath("report.html")
+	assert.True(t, ok)
+	assert.Equal(t, stats.FormatHTML, format)
+}
@@ -0,0 +1,99 @@
+package stats_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	ps := csvStats()
+	ps.CategoryCounts = map[string]int{"code": 2, "markup": 1}
+
+	s := stats.Summarize(ps, 2)
+	assert.Equal(t, 3, s.Totals.Files)
+	assert.Equal(t, []stats.LanguageRow{
+		{Name: "Go", LineTotals: stats.LineTotals{Files: 2, Lines: 15, Code: 12, Comments: 1, Blank: 2}},
+	}, s.Languages)
+	assert.Equal(t, []stats.CategoryRow{{Name: "code", Files: 2}, {Name: "markup", Files: 1}}, s.Categories)
+	if assert.Len(t, s.Largest, 2) {
+		assert.Equal(t, "/work/app/main.go", s.Largest[0].Path)
+	}
+}
+
+func TestWriteMarkdownReport(t *testing.T) {
+	t.Parallel()
+	ps := csvStats()
+	baseline := csvStats()
+	baseline.Files = baseline.Files[:1]
+	baseline.Files[0].LinesCode = 10
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteMarkdownReport(&buf, ps, stats.MarkdownOptions{Baseline: &baseline}))
+	out := buf.String()
+
+	assert.Contains(t, out, "## LintVision: app\n")
+	assert.Contains(t, out, "| 3 (▲ 2) | 18 (▲ 8) | 12 (▲ 2) | 1 | 3 (▲ 2) |")
+	assert.Contains(t, out, "| Go | 2 (▲ 1) | 12 (▲ 2) | 1 | 2 (▲ 1) |")
+	assert.Contains(t, out, "| `main.go` | 10 | 8 |")
+}
+
+func TestWriteMarkdownReport_FitsLimit(t *testing.T) {
+	t.Parallel()
+	ps := stats.ProjectStats{}
+	for i := 0; i < 500; i++ {
+		ps.Files = append(ps.Files, stats.FileStats{
+			Path: fmt.Sprintf("dir/very/long/path/to/file_%03d.go", i), Language: fmt.Sprintf("Lang%03d", i),
+			LinesTotal: i, LinesCode: i,
+		})
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteMarkdownReport(&buf, ps, stats.MarkdownOptions{TopN: 100, MaxBytes: 4000}))
+	assert.LessOrEqual(t, buf.Len(), 4000)
+	assert.Contains(t, buf.String(), "…и ещё")
+}
+
+func TestWriteMarkdownReport_TruncatesOnLineBoundary(t *testing.T) {
+	t.Parallel()
+	ps := csvStats()
+	for _, limit := range []int{30, 100, 150} {
+		var buf bytes.Buffer
+		assert.NoError(t, stats.WriteMarkdownReport(&buf, ps, stats.MarkdownOptions{MaxBytes: limit}))
+		assert.LessOrEqual(t, buf.Len(), limit)
+		assert.True(t, utf8.Valid(buf.Bytes()), "limit %d: output must stay valid UTF-8", limit)
+		assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\n")), "limit %d: output must end with a whole line", limit)
+	}
+
+	ps.Root = "/work/проект"
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteMarkdownReport(&buf, ps, stats.MarkdownOptions{MaxBytes: len("## LintVision: п") - 1}))
+	assert.Equal(t, "## LintVision: ", buf.String(), "without a newline the cut falls on a rune boundary")
+}
+
+// Не параллельный: подменяет os.Stdout.
+func TestAnalyzeAndSaveAs_CompareToStdout(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	prev := filepath.Join(t.TempDir(), "prev.json")
+	assert.NoError(t, stats.SaveStats(stats.ProjectStats{}, prev))
+
+	stdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	_, err := stats.AnalyzeAndSaveAs(root, "", stats.Options{Print: stats.FormatMarkdown, Compare: prev})
+	w.Close()
+	os.Stdout = stdout
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	assert.Contains(t, buf.String(), "| 1 (▲ 1) |")
+}
@@ -0,0 +1,186 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/license"
+	"github.com/rfxxfy/LintVision/lint"
+	"github.com/rfxxfy/LintVision/secrets"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+// Официальная схема OASIS кладётся в testdata командой make sarif-schema;
+// без неё проверка идёт по выдержке из схемы.
+const (
+	sarifSchemaOfficial = "testdata/sarif-schema-2.1.0.json"
+	sarifSchemaExcerpt  = "testdata/sarif-schema-2.1.0-excerpt.json"
+)
+
+// sarifSchemaFile возвращает путь к официальной схеме SARIF, если она
+// скачана, иначе к выдержке.
+func sarifSchemaFile(t *testing.T) string {
+	t.Helper()
+	if _, err := os.Stat(sarifSchemaOfficial); err == nil {
+		return sarifSchemaOfficial
+	}
+	t.Logf("%s not found, validating against %s", sarifSchemaOfficial, sarifSchemaExcerpt)
+	return sarifSchemaExcerpt
+}
+
+func sarifStats() stats.ProjectStats {
+	ps := toolStats()
+	ps.Files[0].Hash = "aa11"
+	ps.Files[0].Lint = []lint.Finding{
+		{Rule: lint.RuleTrailingWhitespace, Severity: lint.SeverityWarning, Path: "/work/app/main.go", Line: 3, Column: 8, Message: "пробелы в конце строки"},
+		{Rule: lint.RuleFinalNewline, Severity: lint.SeverityInfo, Path: "/work/app/main.go", Line: 10, Column: 2, Message: "нет перевода строки в конце файла"},
+	}
+	ps.Secrets = []secrets.Finding{{Path: "/work/app/util/util.go", Line: 2, RuleID: "github-token"}}
+	ps.Licenses = &license.Report{
+		Project:   "MIT",
+		Conflicts: []license.Conflict{{Path: "/work/app/lib/vec.cpp", License: "GPL-3.0-only", Expected: "MIT"}},
+	}
+	ps.Violations = []gates.Violation{
+		{Rule: gates.RuleMaxLinesPerFile, Path: "main.go", Message: "10 строк, допустимо 5"},
+		{Rule: gates.RuleMaxUnknownFiles, Message: "3 файла неизвестного типа"},
+	}
+	return ps
+}
+
+type sarifDoc struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Rules []struct {
+					ID string `json:"id"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		OriginalURIBaseIDs map[string]struct {
+			URI string `json:"uri"`
+		} `json:"originalUriBaseIds"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex int    `json:"ruleIndex"`
+			Level     string `json:"level"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI       string `json:"uri"`
+						URIBaseID string `json:"uriBaseId"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			Fingerprints map[string]string `json:"fingerprints"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteSARIF(&buf, sarifStats()))
+	assert.Empty(t, validateJSONSchema(t, sarifSchemaFile(t), buf.Bytes()))
+	assert.NotEmpty(t, validateJSONSchema(t, sarifSchemaFile(t), []byte(`{"version": "2.0.0", "runs": [{}]}`)))
+
+	var doc sarifDoc
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	if !assert.Len(t, doc.Runs, 1) {
+		return
+	}
+	run := doc.Runs[0]
+	assert.Equal(t, "file:///work/app/", run.OriginalURIBaseIDs["SRCROOT"].URI)
+
+	var ids []string
+	for _, r := range run.Tool.Driver.Rules {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{
+		lint.RuleTrailingWhitespace, lint.RuleFinalNewline, "secrets/github-token",
+		"license/conflict", "gates/" + gates.RuleMaxLinesPerFile,
+	}, ids)
+
+	if !assert.Len(t, run.Results, 5, "project-level violation is not exported") {
+		return
+	}
+	first := run.Results[0]
+	assert.Equal(t, "warning", first.Level)
+	loc := first.Locations[0].PhysicalLocation
+	assert.Equal(t, "main.go", loc.ArtifactLocation.URI)
+	assert.Equal(t, "SRCROOT", loc.ArtifactLocation.URIBaseID)
+	assert.Equal(t, 3, loc.Region.StartLine)
+	assert.Equal(t, 8, loc.Region.StartColumn)
+	assert.Empty(t, first.Fingerprints, "file is not on disk")
+
+	assert.Equal(t, "note", run.Results[1].Level)
+	assert.Equal(t, "util/util.go", run.Results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Len(t, run.Results[3].Fingerprints["lintvision/v1"], 64, "file-level result")
+	assert.Equal(t, 4, run.Results[4].RuleIndex)
+	assert.Equal(t, "main.go", run.Results[4].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	for _, r := range run.Results {
+		assert.NotEmpty(t, r.Locations, r.RuleID)
+	}
+}
+
+// sarifFingerprints записывает content в root/main.go и возвращает
+// отпечатки находок правила стиля в строках lines.
+func sarifFingerprints(t *testing.T, root, content string, lines ...int) []string {
+	t.Helper()
+	path := filepath.Join(root, "main.go")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	ps := stats.ProjectStats{Root: root, Files: []stats.FileStats{{Path: path}}}
+	for _, line := range lines {
+		ps.Files[0].Lint = append(ps.Files[0].Lint,
+			lint.Finding{Rule: lint.RuleTrailingWhitespace, Path: path, Line: line, Column: 1})
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteSARIF(&buf, ps))
+	var doc sarifDoc
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	var fingerprints []string
+	for _, r := range doc.Runs[0].Results {
+		fingerprints = append(fingerprints, r.Fingerprints["lintvision/v1"])
+	}
+	return fingerprints
+}
+
+func TestWriteSARIF_Fingerprints(t *testing.T) {
+	t.Parallel()
+	base := sarifFingerprints(t, t.TempDir(), "package main\nvar x = 1 \n", 2)
+	// Та же строка в другой рабочей копии, сдвинутая правками выше
+	// и с другими пробелами.
+	moved := sarifFingerprints(t, t.TempDir(), "// Package main.\npackage main\n\nvar  x = 1\t\n", 4)
+	changed := sarifFingerprints(t, t.TempDir(), "package main\nvar x = 2 \n", 2)
+	same := sarifFingerprints(t, t.TempDir(), "x \nx \n", 1, 2)
+
+	if assert.Len(t, base, 1) {
+		assert.Len(t, base[0], 64)
+	}
+	assert.Equal(t, base, moved)
+	assert.NotEqual(t, base, changed)
+	if assert.Len(t, same, 2) {
+		assert.NotEqual(t, same[0], same[1], "identical lines")
+	}
+}
+
+func TestWriteSARIF_Analyze(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main  \n"), 0o644))
+	ps, err := stats.Analyze(root, stats.Options{})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteSARIF(&buf, ps))
+	assert.Empty(t, validateJSONSchema(t, sarifSchemaFile(t), buf.Bytes()))
+	assert.Contains(t, buf.String(), `"uri": "main.go"`)
+}
@@ -0,0 +1,54 @@
+package stats_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTable(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteTable(&buf, toolStats()))
+	assert.Equal(t, ""+
+		"Язык    Файлы  Строки  Код  Комментарии  Пустые\n"+
+		"-----------------------------------------------\n"+
+		"Go          2      16   11            4       2\n"+
+		"C++         1       4    3            0       1\n"+
+		"Прочие      1       3    0            0       1\n"+
+		"-----------------------------------------------\n"+
+		"Итого       4      23   14            4       4\n", buf.String())
+}
+
+func TestWriteTableReport_FilesSortedAndLimited(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	err := stats.WriteTableReport(&buf, toolStats(), stats.TableOptions{
+		Files: true, SortBy: stats.SortByCode, TopN: 2,
+	})
+	assert.NoError(t, err)
+
+	out := buf.String()
+	filesTable := out[strings.Index(out, "Файл "):]
+	lines := strings.Split(strings.TrimSpace(filesTable), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[2], "main.go "))
+	assert.True(t, strings.HasPrefix(lines[3], "util/util.go "))
+	assert.NotContains(t, out, "\x1b[")
+}
+
+func TestWriteTableReport_Color(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteTableReport(&buf, toolStats(), stats.TableOptions{Color: true}))
+	assert.Contains(t, buf.String(), "\x1b[1mИтого")
+}
+
+func TestWriteTableReport_UnknownSort(t *testing.T) {
+	t.Parallel()
+	err := stats.WriteTableReport(&bytes.Buffer{}, toolStats(), stats.TableOptions{Files: true, SortBy: "size"})
+	assert.Error(t, err)
+}
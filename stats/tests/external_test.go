@@ -0,0 +1,100 @@
+package stats_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportIssues(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"cmd/main.go": "package main\n",
+		"web/app.js":  "let x = 1;\n",
+		"README.md":   "# app\n",
+	})
+	reports := t.TempDir()
+	golangci := filepath.Join(reports, "golangci.json")
+	assert.NoError(t, os.WriteFile(golangci, []byte(`{"Issues": [
+		{"FromLinter": "govet", "Text": "a", "Pos": {"Filename": "cmd/main.go", "Line": 1, "Column": 1}},
+		{"FromLinter": "errcheck", "Text": "b", "Pos": {"Filename": "`+filepath.ToSlash(root)+`/cmd/main.go", "Line": 1}},
+		{"FromLinter": "govet", "Text": "c", "Pos": {"Filename": "gone/old.go", "Line": 2}}
+	]}`), 0o644))
+	eslint := filepath.Join(reports, "eslint.json")
+	assert.NoError(t, os.WriteFile(eslint, []byte(`[{"filePath": "/ci/checkout/web/app.js", "messages": [
+		{"ruleId": "no-unused-vars", "severity": 1, "message": "unused", "line": 1, "column": 5}
+	]}]`), 0o644))
+
+	ps, err := stats.Analyze(root, stats.Options{Import: []string{golangci, eslint}})
+	assert.NoError(t, err)
+
+	issues := make(map[string]int)
+	for _, f := range ps.Files {
+		issues[ps.RelPath(f.Path)] = len(f.Issues)
+		for _, i := range f.Issues {
+			assert.Equal(t, f.Path, i.Path)
+		}
+	}
+	assert.Equal(t, map[string]int{"cmd/main.go": 2, "web/app.js": 1, "README.md": 0}, issues)
+	assert.Equal(t, map[string]int{"golangci-lint": 2, "eslint": 1}, ps.IssueCounts)
+	assert.Equal(t, map[string]int{"Go": 2, "JavaScript": 1}, ps.IssuesByLanguage)
+	assert.Equal(t, map[string]int{"cmd": 2, "web": 1}, ps.IssuesByDir)
+	if assert.Len(t, ps.UnmatchedIssues, 1) {
+		assert.Equal(t, "gone/old.go", ps.UnmatchedIssues[0].Path)
+	}
+
+	_, err = stats.Analyze(root, stats.Options{Import: []string{filepath.Join(reports, "missing.xml")}})
+	assert.Error(t, err)
+}
+
+func TestImportIssues_ExactRelativePaths(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"main.go":     "package main\n",
+		"pkg/util.go": "package pkg\n",
+	})
+	report := filepath.Join(t.TempDir(), "golangci.json")
+	assert.NoError(t, os.WriteFile(report, []byte(`{"Issues": [
+		{"FromLinter": "govet", "Text": "a", "Pos": {"Filename": "vendor/other/main.go", "Line": 1}},
+		{"FromLinter": "govet", "Text": "b", "Pos": {"Filename": "cmd/x/util.go", "Line": 1}},
+		{"FromLinter": "govet", "Text": "c", "Pos": {"Filename": "/ci/checkout/pkg/util.go", "Line": 1}},
+		{"FromLinter": "govet", "Text": "d", "Pos": {"Filename": "`+filepath.ToSlash(root)+`/lib/main.go", "Line": 1}}
+	]}`), 0o644))
+
+	ps, err := stats.Analyze(root, stats.Options{Import: []string{report}})
+	assert.NoError(t, err)
+	issues := make(map[string]int)
+	for _, f := range ps.Files {
+		issues[ps.RelPath(f.Path)] = len(f.Issues)
+	}
+	assert.Equal(t, map[string]int{"main.go": 0, "pkg/util.go": 1}, issues,
+		"only absolute paths from another checkout are matched by suffix")
+	assert.Len(t, ps.UnmatchedIssues, 3)
+
+	var buf bytes.Buffer
+	streamed, err := stats.StreamJSONL(root, &buf, stats.Options{Import: []string{report}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"golangci-lint": 1}, streamed.IssueCounts)
+	assert.Equal(t, ps.UnmatchedIssues, streamed.UnmatchedIssues)
+}
+
+func TestStreamJSONL_ImportIssues(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{"a.go": "package a\n"})
+	report := filepath.Join(t.TempDir(), "checkstyle.xml")
+	assert.NoError(t, os.WriteFile(report, []byte(`<checkstyle><file name="a.go">
+		<error line="1" severity="warning" message="m" source="r"/></file></checkstyle>`), 0o644))
+
+	var buf bytes.Buffer
+	ps, err := stats.StreamJSONL(root, &buf, stats.Options{Import: []string{report}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"checkstyle": 1}, ps.IssueCounts)
+	assert.Contains(t, buf.String(), `"issues":[{"tool":"checkstyle"`)
+}
@@ -0,0 +1,91 @@
+package stats_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/gates"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func gateStats() stats.ProjectStats {
+	return stats.ProjectStats{
+		Root: "/work/app",
+		Files: []stats.FileStats{
+			{Path: "/work/app/big.go", Category: "code", Language: "Go", Hash: "h1", Size: 4096,
+				LinesTotal: 600, LinesCode: 590, LinesComments: 5, LinesBlank: 5},
+			{Path: "/work/app/copy.go", Category: "code", Language: "Go", Hash: "h1", Size: 4096,
+				LinesTotal: 600, LinesCode: 590, LinesComments: 5, LinesBlank: 5},
+			{Path: "/work/app/lib.py", Category: "code", Language: "Python", Hash: "h2", Size: 100,
+				LinesTotal: 10, LinesCode: 5, LinesComments: 5},
+			{Path: "/work/app/blob.dat", Category: "unknown", Hash: "h3", Size: 1 << 20},
+		},
+	}
+}
+
+func TestEvaluateGates_Disabled(t *testing.T) {
+	t.Parallel()
+	ps := gateStats()
+	stats.EvaluateGates(&ps, gates.Config{})
+	assert.Empty(t, ps.Violations)
+}
+
+func TestEvaluateGates(t *testing.T) {
+	t.Parallel()
+	maxLines, maxUnknown := 500, 0
+	maxBytes := int64(64 * 1024)
+	maxDup := 10.0
+	cfg := gates.Config{
+		MaxLinesPerFile:       &maxLines,
+		MinCommentRatio:       map[string]float64{"Go": 0.1, "*": 0.6},
+		MaxDuplicationPercent: &maxDup,
+		MaxFileBytes:          &maxBytes,
+		MaxUnknownFiles:       &maxUnknown,
+	}
+
+	ps := gateStats()
+	stats.EvaluateGates(&ps, cfg)
+
+	rules := make(map[string][]gates.Violation)
+	for _, v := range ps.Violations {
+		rules[v.Rule] = append(rules[v.Rule], v)
+	}
+	if assert.Len(t, rules[gates.RuleMaxLinesPerFile], 2) {
+		assert.Equal(t, "big.go", rules[gates.RuleMaxLinesPerFile][0].Path)
+		assert.Equal(t, 600.0, rules[gates.RuleMaxLinesPerFile][0].Actual)
+	}
+	if assert.Len(t, rules[gates.RuleMinCommentRatio], 2) {
+		assert.Equal(t, "Go", rules[gates.RuleMinCommentRatio][0].Language)
+		assert.Equal(t, "Python", rules[gates.RuleMinCommentRatio][1].Language)
+	}
+	if assert.Len(t, rules[gates.RuleMaxDuplicationPercent], 1) {
+		assert.InDelta(t, 600.0*100/1210, rules[gates.RuleMaxDuplicationPercent][0].Actual, 0.001)
+	}
+	assert.Len(t, rules[gates.RuleMaxFileBytes], 1)
+	assert.Len(t, rules[gates.RuleMaxUnknownFiles], 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteViolations(&buf, ps.Violations))
+	assert.Contains(t, buf.String(), "[max-lines-per-file] big.go: 600 строк, допустимо не более 500\n")
+}
+
+func TestEvaluateGates_SkipsVCSMetadata(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"main.go":                      "package main\n",
+		".git/HEAD":                    "ref: refs/heads/main\n",
+		".git/hooks/pre-commit.sample": "#!/bin/sh\n",
+		".git/objects/pack/p.pack":     string(make([]byte, 4096)),
+	})
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	maxUnknown := 0
+	maxBytes := int64(1024)
+	stats.EvaluateGates(&ps, gates.Config{MaxUnknownFiles: &maxUnknown, MaxFileBytes: &maxBytes})
+	assert.Empty(t, ps.Violations)
+	assert.Len(t, ps.Files, 1)
+}
@@ -0,0 +1,60 @@
+package stats_test
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/rfxxfy/LintVision/internal/gittest"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyHistory(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	first := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	gittest.Commit(t, root, first, map[string]string{
+		"big.go":   "package a\n\nvar a = 1\nvar b = 2\nvar c = 3\nvar d = 4\n",
+		"small.go": "package a\n",
+	})
+	gittest.Commit(t, root, first.Add(time.Hour), map[string]string{"small.go": "package a\n\nvar s = 1\n"})
+	gittest.Commit(t, root, first.Add(2*time.Hour), map[string]string{"small.go": "package a\n\nvar s = 2\n"})
+	createTestTree(t, root, map[string]string{"untracked.go": "package a\n"})
+
+	ps, err := stats.Analyze(root, stats.Options{History: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &stats.HistoryInfo{Commits: 3}, ps.History)
+
+	byPath := make(map[string]*stats.FileHistory)
+	for _, f := range ps.Files {
+		byPath[ps.RelPath(f.Path)] = f.History
+	}
+	assert.Equal(t, &stats.FileHistory{Commits: 1, LinesAdded: 6, LastModified: first, Hotspot: 0.333}, byPath["big.go"])
+	assert.Equal(t, &stats.FileHistory{Commits: 3, LinesAdded: 4, LinesRemoved: 1,
+		LastModified: first.Add(2 * time.Hour), Hotspot: 0.4}, byPath["small.go"])
+	assert.Nil(t, byPath["untracked.go"])
+
+	hotspots := stats.Hotspots(ps, 1)
+	if assert.Len(t, hotspots, 1) {
+		assert.Equal(t, "small.go", ps.RelPath(hotspots[0].Path))
+	}
+}
+
+func TestApplyHistory_NotARepo(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{"a.go": "package a\n"})
+
+	_, err := stats.Analyze(root, stats.Options{History: true})
+	assert.ErrorContains(t, err, "not a git work tree")
+
+	_, err = stats.StreamJSONL(root, io.Discard, stats.Options{History: true})
+	assert.ErrorContains(t, err, "not supported with streaming")
+}
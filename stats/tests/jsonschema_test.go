@@ -0,0 +1,203 @@
+package stats_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// validateJSONSchema проверяет doc по JSON Schema из файла schemaPath.
+// Поддерживается подмножество ключевых слов, которого достаточно для
+// схем в testdata и schema/: type, enum, required, properties,
+// additionalProperties, items, minItems, uniqueItems, minimum, maximum,
+// pattern, anyOf, oneOf, allOf и локальные $ref; прочие ключевые слова
+// (format, default и т. п.) игнорируются.
+func validateJSONSchema(t *testing.T, schemaPath string, doc []byte) []string {
+	t.Helper()
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		t.Fatalf("cannot read schema: %v", err)
+	}
+	var schema, value any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	dec := json.NewDecoder(strings.NewReader(string(doc)))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		t.Fatalf("invalid document: %v", err)
+	}
+	v := schemaValidator{root: schema}
+	v.validate(schema, value, "$")
+	return v.errors
+}
+
+type schemaValidator struct {
+	root   any
+	errors []string
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+// resolve находит схему по локальной ссылке вида "#/definitions/name".
+func (v *schemaValidator) resolve(ref string) any {
+	node := v.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		obj, _ := node.(map[string]any)
+		node = obj[part]
+	}
+	return node
+}
+
+func (v *schemaValidator) validate(schema, value any, path string) {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		v.validate(v.resolve(ref), value, path)
+	}
+	if typ, ok := s["type"]; ok && !matchesType(typ, value) {
+		v.fail(path, "type %v expected, got %T", typ, value)
+		return
+	}
+	if enum, ok := s["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+			}
+		}
+		if !found {
+			v.fail(path, "%v is not one of %v", value, enum)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok && v.countMatches(anyOf, value, path) == 0 {
+		v.fail(path, "does not match anyOf")
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		if n := v.countMatches(oneOf, value, path); n != 1 {
+			v.fail(path, "matches %d of oneOf, want exactly 1", n)
+		}
+	}
+	if allOf, ok := s["allOf"].([]any); ok {
+		for _, sub := range allOf {
+			v.validate(sub, value, path)
+		}
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(s, val, path)
+	case []any:
+		if lo, ok := s["minItems"].(float64); ok && float64(len(val)) < lo {
+			v.fail(path, "fewer than %v items", lo)
+		}
+		if unique, _ := s["uniqueItems"].(bool); unique {
+			for i := range val {
+				for j := i + 1; j < len(val); j++ {
+					if reflect.DeepEqual(val[i], val[j]) {
+						v.fail(path, "items %d and %d are equal", i, j)
+					}
+				}
+			}
+		}
+		for i, item := range val {
+			v.validate(s["items"], item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case json.Number:
+		n, _ := val.Float64()
+		if lo, ok := s["minimum"].(float64); ok && n < lo {
+			v.fail(path, "%v is less than %v", n, lo)
+		}
+		if hi, ok := s["maximum"].(float64); ok && n > hi {
+			v.fail(path, "%v is greater than %v", n, hi)
+		}
+	case string:
+		if pattern, ok := s["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(val) {
+			v.fail(path, "%q does not match %s", val, pattern)
+		}
+	}
+}
+
+// countMatches возвращает число схем из alts, которым соответствует value.
+func (v *schemaValidator) countMatches(alts []any, value any, path string) int {
+	n := 0
+	for _, alt := range alts {
+		sub := schemaValidator{root: v.root}
+		sub.validate(alt, value, path)
+		if len(sub.errors) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *schemaValidator) validateObject(s, obj map[string]any, path string) {
+	if required, ok := s["required"].([]any); ok {
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+	}
+	props, _ := s["properties"].(map[string]any)
+	for name, val := range obj {
+		if prop, ok := props[name]; ok {
+			v.validate(prop, val, path+"."+name)
+			continue
+		}
+		switch extra := s["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				v.fail(path, "unexpected property %q", name)
+			}
+		case map[string]any:
+			v.validate(extra, val, path+"."+name)
+		}
+	}
+}
+
+func matchesType(typ, value any) bool {
+	if list, ok := typ.([]any); ok {
+		for _, t := range list {
+			if matchesType(t, value) {
+				return true
+			}
+		}
+		return false
+	}
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package stats_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/internal/gittest"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyBlame(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gittest.CommitAs(t, root, "Alice", "alice@example.com", when, map[string]string{
+		"core/a.go":   "package core\n\nvar a = 1\nvar b = 2\n",
+		"web/app.js":  "let x = 1;\n",
+		"README.md":   "# readme\n",
+		".mailmap":    "Bob Jones <bob@example.com> <bob@old.example>\n",
+		"vendor/v.go": "package v\n",
+	})
+	gittest.CommitAs(t, root, "Bob", "bob@old.example", when, map[string]string{
+		"web/app.js": "let x = 1;\nlet y = 2;\n",
+		"web/ui.js":  "let z = 1;\n",
+	})
+	gittest.CommitAs(t, root, "Carol", "carol@example.com", when, map[string]string{
+		"web/app.js": "let x = 1;\nlet y = 2;\nlet c = 3;\n",
+	})
+
+	ps, err := stats.Analyze(root, stats.Options{Blame: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	byPath := make(map[string][]history.AuthorLines)
+	for _, f := range ps.Files {
+		byPath[ps.RelPath(f.Path)] = f.Authors
+	}
+	alice := history.AuthorLines{Name: "Alice", Email: "alice@example.com"}
+	bob := history.AuthorLines{Name: "Bob Jones", Email: "bob@example.com"}
+	carol := history.AuthorLines{Name: "Carol", Email: "carol@example.com"}
+	with := func(a history.AuthorLines, lines int) history.AuthorLines {
+		a.Lines = lines
+		return a
+	}
+	assert.Equal(t, []history.AuthorLines{with(alice, 4)}, byPath["core/a.go"])
+	assert.Equal(t, []history.AuthorLines{with(alice, 1), with(bob, 1), with(carol, 1)}, byPath["web/app.js"])
+	assert.Nil(t, byPath["README.md"], "only source code is blamed")
+	assert.Nil(t, byPath["vendor/v.go"], "vendored code is not blamed")
+
+	if !assert.NotNil(t, ps.Ownership) {
+		return
+	}
+	assert.Equal(t, 8, ps.Ownership.Lines)
+	assert.Equal(t, []history.AuthorLines{with(alice, 5), with(bob, 2), with(carol, 1)}, ps.Ownership.Authors)
+	assert.Equal(t, 1, ps.Ownership.BusFactor)
+	assert.Equal(t, stats.Ownership{Lines: 4, BusFactor: 2,
+		Authors: []history.AuthorLines{with(bob, 2), with(alice, 1), with(carol, 1)}}, ps.Ownership.ByDir["web"])
+	assert.Equal(t, []string{"core", "web"}, ps.Ownership.DirsByRisk())
+
+	ps.DropGenerated()
+	assert.Equal(t, 8, ps.Ownership.Lines, "ownership survives re-aggregation")
+}
+
+func TestApplyBlame_Streaming(t *testing.T) {
+	t.Parallel()
+	_, err := stats.StreamJSONL(t.TempDir(), io.Discard, stats.Options{Blame: true})
+	assert.ErrorContains(t, err, "not supported with streaming")
+}
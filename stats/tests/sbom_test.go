@@ -0,0 +1,127 @@
+package stats_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/deps"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func sbomStats() stats.ProjectStats {
+	return stats.ProjectStats{
+		Root: "/work/app",
+		Files: []stats.FileStats{
+			{Path: "/work/app/main.go", Ext: ".go", Category: "code", Hash: "abc123"},
+		},
+		Dependencies: []deps.Dependency{
+			{Name: "github.com/a/b", Version: "v1.0.0", Ecosystem: deps.EcosystemGo, Manifest: "/work/app/go.mod"},
+			{Name: "golang.org/x/sys", Version: "v0.30.0", Ecosystem: deps.EcosystemGo, Manifest: "/work/app/go.mod", Indirect: true},
+		},
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteCycloneDX(&buf, sbomStats()))
+
+	var bom struct {
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		Metadata    struct {
+			Component struct {
+				Name string `json:"name"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			Type   string `json:"type"`
+			Name   string `json:"name"`
+			PURL   string `json:"purl"`
+			Hashes []struct {
+				Alg     string `json:"alg"`
+				Content string `json:"content"`
+			} `json:"hashes"`
+		} `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &bom))
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Equal(t, "app", bom.Metadata.Component.Name)
+	assert.Len(t, bom.Components, 3)
+	assert.Equal(t, "pkg:golang/github.com/a/b@v1.0.0", bom.Components[0].PURL)
+	assert.Equal(t, "file", bom.Components[2].Type)
+	assert.Equal(t, "main.go", bom.Components[2].Name)
+	assert.Equal(t, "SHA-256", bom.Components[2].Hashes[0].Alg)
+	assert.Equal(t, "abc123", bom.Components[2].Hashes[0].Content)
+	assert.Equal(t, []string{"pkg:golang/github.com/a/b@v1.0.0"}, bom.Dependencies[0].DependsOn)
+}
+
+func TestWriteSPDX(t *testing.T) {
+	t.Parallel()
+	ps := sbomStats()
+	fileSHA1 := sha1.Sum([]byte("package main\n"))
+	ps.Files[0].SHA1 = hex.EncodeToString(fileSHA1[:])
+	ps.Files[0].License = "(MIT OR Apache-2.0)"
+	code := sha1.Sum([]byte(hex.EncodeToString(fileSHA1[:])))
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteSPDX(&buf, ps))
+
+	out := buf.String()
+	assert.Contains(t, out, "SPDXVersion: SPDX-2.3\n")
+	assert.Contains(t, out, "DocumentName: app\n")
+	assert.Contains(t, out, "ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/a/b@v1.0.0\n")
+	assert.Contains(t, out, "FilesAnalyzed: true\nPackageVerificationCode: "+hex.EncodeToString(code[:])+"\n")
+	assert.Contains(t, out, "FileName: ./main.go\n")
+	assert.Contains(t, out, "FileChecksum: SHA1: "+hex.EncodeToString(fileSHA1[:])+"\n")
+	assert.Contains(t, out, "FileChecksum: SHA256: abc123\n")
+	assert.Contains(t, out, "LicenseInfoInFile: MIT\nLicenseInfoInFile: Apache-2.0\n")
+	assert.Contains(t, out, "Relationship: SPDXRef-Package-app CONTAINS SPDXRef-File-1\n")
+}
+
+func TestWriteSPDX_ClonedTreeRemoved(t *testing.T) {
+	t.Parallel()
+	root := filepath.Join(t.TempDir(), "app")
+	createTestTree(t, root, map[string]string{"main.go": "package main\n"})
+	ps, err := stats.ComputeProjectStatsFromDir(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, os.RemoveAll(root))
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteSPDX(&buf, ps))
+	fileSHA1 := sha1.Sum([]byte("package main\n"))
+	out := buf.String()
+	assert.Contains(t, out, "FilesAnalyzed: true\n")
+	assert.Contains(t, out, "FileChecksum: SHA1: "+hex.EncodeToString(fileSHA1[:])+"\n")
+}
+
+func TestWriteSPDX_NoChecksums(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteSPDX(&buf, sbomStats()))
+
+	out := buf.String()
+	assert.Contains(t, out, "PackageName: app\nSPDXID: SPDXRef-Package-app\nPackageDownloadLocation: NOASSERTION\nFilesAnalyzed: false\n")
+	assert.NotContains(t, out, "PackageVerificationCode:")
+	assert.NotContains(t, out, "FileName:")
+	assert.NotContains(t, out, " CONTAINS ")
+}
+
+func TestWriteStats_UnknownFormat(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.Error(t, stats.WriteStats(&buf, sbomStats(), "xml"))
+}
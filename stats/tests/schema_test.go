@@ -0,0 +1,119 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+const schemaFile = "../schema/report.schema.json"
+
+func TestJSONSchema_UpToDate(t *testing.T) {
+	got, err := stats.JSONSchema()
+	assert.NoError(t, err)
+	if *update {
+		assert.NoError(t, os.WriteFile(schemaFile, got, 0o644))
+	}
+	want, err := os.ReadFile(schemaFile)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "schema is stale: run go test ./stats/tests -run JSONSchema -update")
+}
+
+func TestJSONSchema_DescribesReportFields(t *testing.T) {
+	t.Parallel()
+	data, err := stats.JSONSchema()
+	assert.NoError(t, err)
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &schema))
+
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n// TODO: x\n"), 0o644))
+	ps, err := stats.Analyze(root, stats.Options{})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteJSON(&buf, ps))
+	var report map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	for key := range report {
+		assert.Contains(t, schema.Defs["ProjectStats"].Properties, key)
+	}
+
+	var files []map[string]any
+	assert.NoError(t, json.Unmarshal(report["files"], &files))
+	for key := range files[0] {
+		assert.Contains(t, schema.Defs["FileStats"].Properties, key)
+	}
+}
+
+func TestJSONSchema_ValidatesReport(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteJSON(&buf, sarifStats()))
+	assert.Empty(t, validateJSONSchema(t, schemaFile, buf.Bytes()))
+}
+
+func TestAnalyze_Metadata(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o644))
+
+	ps, err := stats.Analyze(root, stats.Options{ExcludeGenerated: true})
+	assert.NoError(t, err)
+	assert.Equal(t, stats.SchemaVersion, ps.SchemaVersion)
+	if assert.NotNil(t, ps.Metadata) {
+		assert.Equal(t, "LintVision", ps.Metadata.Tool)
+		assert.Equal(t, stats.ToolVersion, ps.Metadata.ToolVersion)
+		assert.True(t, ps.Metadata.Options.ExcludeGenerated)
+		assert.False(t, ps.Metadata.Timestamp.IsZero())
+		assert.Empty(t, ps.Metadata.Commit)
+	}
+}
+
+func TestDecodeStats_V1(t *testing.T) {
+	t.Parallel()
+	v1 := `{
+  "files": [
+    {"path": "src/main.go", "ext": ".go", "category": "code", "lines_total": 10, "lines_code": 8, "lines_comments": 1, "lines_blank": 1},
+    {"path": "src/main_test.go", "ext": ".go", "category": "code", "lines_total": 4, "lines_code": 4, "lines_comments": 0, "lines_blank": 0}
+  ],
+  "category_counts": {"code": 2},
+  "hidden_files": 1,
+  "hidden_dirs": 0,
+  "non_hidden_dirs": 1
+}`
+	ps, err := stats.DecodeStats([]byte(v1))
+	assert.NoError(t, err)
+	assert.Equal(t, stats.SchemaVersion, ps.SchemaVersion)
+	assert.Equal(t, "Go", ps.Files[0].Language)
+	assert.True(t, ps.Files[1].Test)
+	assert.Equal(t, 14, ps.Totals.Lines)
+	assert.Equal(t, 4, ps.TestTotals.Code)
+	assert.Equal(t, 1, ps.HiddenFiles)
+}
+
+func TestDecodeStats_NewerVersion(t *testing.T) {
+	t.Parallel()
+	_, err := stats.DecodeStats([]byte(`{"schema_version": 999, "files": []}`))
+	assert.Error(t, err)
+}
+
+func TestLoadStats_RoundTrip(t *testing.T) {
+	t.Parallel()
+	outFile := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, stats.SaveStats(sampleStats(), outFile))
+
+	ps, err := stats.LoadStats(outFile)
+	assert.NoError(t, err)
+	assert.Equal(t, stats.SchemaVersion, ps.SchemaVersion)
+	assert.Equal(t, "main.go", ps.Files[0].Path)
+}
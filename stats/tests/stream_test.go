@@ -0,0 +1,116 @@
+package stats_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+// readJSONL разбирает вывод JSON Lines построчно.
+func readJSONL(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var rec map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &rec), scanner.Text())
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestStreamJSONL(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\n// doc\nfunc main() {}\n"), 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "lib"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "lib", "lib.go"), []byte("package lib\n"), 0o644))
+
+	var buf bytes.Buffer
+	ps, err := stats.StreamJSONL(root, &buf, stats.Options{ExcludeGenerated: true})
+	assert.NoError(t, err)
+	assert.Empty(t, ps.Files)
+	assert.Equal(t, 1, ps.Totals.Files)
+
+	records := readJSONL(t, buf.Bytes())
+	assert.Len(t, records, 2)
+	assert.Equal(t, stats.RecordFile, records[0]["type"])
+	assert.Equal(t, filepath.Join(root, "main.go"), records[0]["path"])
+	assert.Equal(t, float64(2), records[0]["lines_code"])
+
+	summary := records[1]
+	assert.Equal(t, stats.RecordSummary, summary["type"])
+	assert.NotContains(t, summary, "files")
+	assert.Equal(t, float64(4), summary["totals"].(map[string]any)["lines_total"])
+	assert.Equal(t, float64(1), summary["languages"].(map[string]any)["Go"].(map[string]any)["files"])
+}
+
+func TestWriteJSONL_MatchesStreaming(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteJSONL(&buf, toolStats()))
+
+	records := readJSONL(t, buf.Bytes())
+	assert.Len(t, records, 5)
+	for _, rec := range records[:4] {
+		assert.Equal(t, stats.RecordFile, rec["type"])
+	}
+	assert.Equal(t, stats.RecordSummary, records[4]["type"])
+	assert.Contains(t, records[4]["languages"], "C++")
+}
+
+func TestAnalyzeAndSaveAs_JSONL(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o644))
+	outFile := filepath.Join(t.TempDir(), "out.jsonl")
+
+	_, err := stats.AnalyzeAndSaveAs(root, outFile, stats.Options{Format: stats.FormatJSONL})
+	assert.NoError(t, err)
+	data, err := os.ReadFile(outFile)
+	assert.NoError(t, err)
+	assert.Len(t, readJSONL(t, data), 2)
+}
+
+func TestStreamJSONL_MatchesAnalyze(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	createTestTree(t, root, map[string]string{
+		"LICENSE":     corpusText(t, "MIT"),
+		"a.go":        "// SPDX-License-Identifier: MIT\r\npackage a\r\n",
+		"b.go":        "// SPDX-License-Identifier: GPL-3.0-only\r\npackage b\r\n",
+		"c.go":        "package c\n\nfunc C() {}\n",
+		"d.go":        "// SPDX-License-Identifier: MIT\r\npackage d\r\n",
+		"vendor/v.go": "package v\r\n\r\n",
+	})
+	opts := stats.Options{ExcludeGenerated: true}
+	want, err := stats.Analyze(root, opts)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	got, err := stats.StreamJSONL(root, &buf, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, want.Totals, got.Totals)
+	assert.Equal(t, want.LintCounts, got.LintCounts)
+	assert.Equal(t, want.Licenses, got.Licenses)
+	if assert.NotNil(t, got.Licenses) {
+		assert.Equal(t, "MIT", got.Licenses.Project)
+		assert.Len(t, got.Licenses.Conflicts, 1)
+	}
+
+	// CRLF принят в проекте: находок crlf нет ни в одной записи.
+	records := readJSONL(t, buf.Bytes())
+	assert.Len(t, records, len(want.Files)+1)
+	assert.NotContains(t, buf.String(), `"rule":"crlf"`)
+	for _, f := range want.Files {
+		for _, l := range f.Lint {
+			assert.NotEqual(t, "crlf", l.Rule, f.Path)
+		}
+	}
+}
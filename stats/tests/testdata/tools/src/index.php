@@ -0,0 +1,3 @@
+<?php
+// Greeting page.
+echo "hi";
@@ -0,0 +1,8 @@
+#include <vector>
+
+// Sum adds the values.
+int sum(const std::vector<int> &v) {
+	int s = 0;
+	for (int x : v) s += x;
+	return s;
+}
@@ -0,0 +1,9 @@
+// Package main is an example.
+package main
+
+import "fmt"
+
+// main prints a greeting.
+func main() {
+	fmt.Println("hi") // greet
+}
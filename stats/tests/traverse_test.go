@@ -1,6 +1,7 @@
 package stats_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -59,6 +60,21 @@ func TestScanDir(t *testing.T) {
 			wantHiddenD: 1, // .hiddendir
 			wantNonHidD: 3, // dir, dir/visible
 		},
+		{
+			name: "vcs metadata",
+			files: map[string]string{
+				"main.go":          "package main",
+				".git/HEAD":        "ref: refs/heads/main",
+				".git/objects/ab":  "\x00",
+				"sub/.hg/store":    "data",
+				"sub/.svn/entries": "12",
+				".gitignore":       "bin/",
+			},
+			wantFiles:   []string{"main.go", ".gitignore"},
+			wantHiddenF: 1, // .gitignore
+			wantHiddenD: 0,
+			wantNonHidD: 2, // root, sub
+		},
 		{
 			name: "only hidden",
 			files: map[string]string{
@@ -123,3 +139,27 @@ func TestComputeProjectStatsFromDir(t *testing.T) {
 	assert.Equal(t, 1, ps.HiddenFiles)
 	assert.Equal(t, 2, ps.NonHiddenDirs)
 }
+
+func TestComputeProjectStatsFromDir_SkipsVCSDirs(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	createTestTree(t, tmpDir, map[string]string{
+		"main.go":                "package main\n\nfunc main() {}\n",
+		".git/HEAD":              "ref: refs/heads/main\n",
+		".git/hooks/pre-push.sh": "#!/bin/sh\nexit 0\n",
+		"lib/.hg/hgrc":           "[paths]\n",
+		"lib/.svn/entries":       "12\n",
+		"lib/lib.go":             "package lib\n",
+	})
+
+	ps, err := stats.ComputeProjectStatsFromDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, stats.LineTotals{Files: 2, Lines: 4, Code: 3, Blank: 1}, ps.Totals)
+	assert.Equal(t, map[string]int{"code": 2}, ps.CategoryCounts)
+	assert.Zero(t, ps.HiddenDirs)
+
+	var buf bytes.Buffer
+	streamed, err := stats.StreamJSONL(tmpDir, &buf, stats.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, ps.Totals, streamed.Totals)
+}
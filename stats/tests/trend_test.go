@@ -0,0 +1,69 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/internal/gittest"
+	"github.com/rfxxfy/LintVision/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTrend(t *testing.T) {
+	t.Parallel()
+	root := gittest.Init(t)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	gittest.Commit(t, root, start, map[string]string{"main.go": "package main\n"})
+	gittest.Commit(t, root, start.Add(time.Hour), map[string]string{"web/app.js": "let x = 1;\n"})
+	gittest.Commit(t, root, start.Add(2*time.Hour), map[string]string{
+		"main.go": "package main\n\nfunc main() {}\n",
+		"util.go": "package main\n",
+	})
+	createTestTree(t, root, map[string]string{"uncommitted.go": "package main\n"})
+
+	var progress []int
+	trend, err := stats.ComputeTrend(root, stats.TrendOptions{
+		Sample:   history.SampleEvery,
+		Every:    1,
+		Progress: func(done, total int) { progress = append(progress, done*10+total) },
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []int{13, 23, 33}, progress)
+	if !assert.Len(t, trend.Points, 3) {
+		return
+	}
+	assert.Equal(t, start, trend.Points[0].Time)
+	assert.Len(t, trend.Points[0].Commit, 40)
+	assert.Equal(t, map[string]stats.LineTotals{"Go": {Files: 1, Lines: 1, Code: 1}}, trend.Points[0].Languages)
+	assert.Equal(t, stats.LineTotals{Files: 3, Lines: 5, Code: 4, Blank: 1}, trend.Points[2].Totals,
+		"snapshots contain committed files only")
+	assert.Equal(t, stats.LineTotals{Files: 2, Lines: 4, Code: 3, Blank: 1}, trend.Points[2].Languages["Go"])
+	assert.Equal(t, []string{"Go", "JavaScript"}, trend.LanguageNames())
+
+	limited, err := stats.ComputeTrend(root, stats.TrendOptions{Sample: history.SampleEvery, Every: 1, Max: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, limited.Points, 2) {
+		assert.Equal(t, trend.Points[1:], limited.Points, "Max keeps the latest snapshots")
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, stats.WriteTrend(&buf, trend, stats.FormatCSV))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, "time,commit,tag,language,files,lines_total,lines_code,lines_comments,lines_blank", lines[0])
+	assert.Len(t, lines, 1+2+3+3)
+	assert.Equal(t, "2024-01-01T14:00:00Z,"+trend.Points[2].Commit+",,JavaScript,1,1,1,0,0", lines[len(lines)-1])
+
+	buf.Reset()
+	assert.NoError(t, stats.WriteTrend(&buf, trend, ""))
+	var decoded stats.Trend
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, trend.Points, decoded.Points)
+
+	assert.ErrorContains(t, stats.WriteTrend(&buf, trend, "xml"), "unknown trend format")
+}
@@ -10,6 +10,29 @@ import (
 
 func ScanDir(root string) ([]string, int, int, int, error) {
 	var paths []string
+	hiddenFiles, hiddenDirs, nonHiddenDirs, err := walkDir(root, func(path string) error {
+		paths = append(paths, path)
+		return nil
+	})
+
+	if err != nil {
+		logging.Error("ScanDir: walk error on %s: %v", root, err)
+	} else {
+		logging.Info("ScanDir: found %d files (%d hidden files) under %s; dirs: %d hidden, %d non-hidden",
+			len(paths), hiddenFiles, root, hiddenDirs, nonHiddenDirs)
+	}
+
+	return paths, hiddenFiles, hiddenDirs, nonHiddenDirs, err
+}
+
+// vcsDirs — служебные директории систем контроля версий. Их содержимое
+// не относится к проекту, поэтому обход их пропускает.
+var vcsDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// walkDir обходит root, вызывая visit для каждого файла, и считает
+// скрытые файлы и директории. Директории из vcsDirs пропускаются
+// и не учитываются.
+func walkDir(root string, visit func(path string) error) (int, int, int, error) {
 	hiddenFiles := 0
 	hiddenDirs := 0
 	nonHiddenDirs := 0
@@ -24,6 +47,9 @@ func ScanDir(root string) ([]string, int, int, int, error) {
 		isHidden := strings.HasPrefix(name, ".")
 
 		if d.IsDir() {
+			if path != root && vcsDirs[name] {
+				return filepath.SkipDir
+			}
 			if isHidden {
 				hiddenDirs++
 			} else {
@@ -35,18 +61,9 @@ func ScanDir(root string) ([]string, int, int, int, error) {
 		if isHidden {
 			hiddenFiles++
 		}
-		paths = append(paths, path)
-		return nil
+		return visit(path)
 	})
-
-	if err != nil {
-		logging.Error("ScanDir: walk error on %s: %v", root, err)
-	} else {
-		logging.Info("ScanDir: found %d files (%d hidden files) under %s; dirs: %d hidden, %d non-hidden",
-			len(paths), hiddenFiles, root, hiddenDirs, nonHiddenDirs)
-	}
-
-	return paths, hiddenFiles, hiddenDirs, nonHiddenDirs, err
+	return hiddenFiles, hiddenDirs, nonHiddenDirs, err
 }
 
 func ComputeProjectStatsFromDir(root string) (ProjectStats, error) {
@@ -55,7 +72,7 @@ func ComputeProjectStatsFromDir(root string) (ProjectStats, error) {
 		return ProjectStats{}, err
 	}
 
-	ps, err := ComputeProjectStats(files)
+	ps, err := computeProjectStats(root, files)
 	if err != nil {
 		return ps, err
 	}
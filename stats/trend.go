@@ -0,0 +1,197 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/logging"
+)
+
+// TrendOptions задаёт, какие коммиты истории войдут в тренд.
+type TrendOptions struct {
+	// Sample — способ выбора коммитов: history.SampleEvery, SampleWeekly
+	// или SampleTags; Every — шаг для SampleEvery.
+	Sample string `json:"sample"`
+	Every  int    `json:"every,omitempty"`
+	// Max ограничивает число снимков последними Max (0 — без ограничения).
+	Max int `json:"max,omitempty"`
+	// Progress вызывается после обработки каждого снимка.
+	Progress func(done, total int) `json:"-"`
+}
+
+// TrendPoint — итоги по одному снимку истории.
+type TrendPoint struct {
+	Commit    string                `json:"commit"`
+	Time      time.Time             `json:"time"`
+	Tag       string                `json:"tag,omitempty"`
+	Totals    LineTotals            `json:"totals"`
+	Languages map[string]LineTotals `json:"languages"`
+}
+
+// Trend — ряд снимков от старых к новым.
+type Trend struct {
+	Root    string       `json:"root,omitempty"`
+	Options TrendOptions `json:"options"`
+	Points  []TrendPoint `json:"points"`
+}
+
+// ComputeTrend считает строки по языкам в выбранных коммитах истории
+// git-репозитория root. Каждый снимок извлекается через git archive во
+// временную директорию, так что рабочая копия не меняется. Для снимков
+// считаются только строки: зависимости, лицензии и владельцы не нужны.
+func ComputeTrend(root string, opts TrendOptions) (Trend, error) {
+	trend := Trend{Root: root, Options: opts}
+	commits, err := history.SampleCommits(root, opts.Sample, opts.Every)
+	if err != nil {
+		return trend, err
+	}
+	if opts.Max > 0 && len(commits) > opts.Max {
+		commits = commits[len(commits)-opts.Max:]
+	}
+	tmp, err := os.MkdirTemp("", "lintvision-trend-*")
+	if err != nil {
+		return trend, err
+	}
+	defer os.RemoveAll(tmp)
+
+	for i, c := range commits {
+		point, err := computeSnapshot(root, c, filepath.Join(tmp, strconv.Itoa(i)))
+		if err != nil {
+			logging.Error("ComputeTrend: snapshot %s failed: %v", c.SHA, err)
+			return trend, err
+		}
+		trend.Points = append(trend.Points, point)
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(commits))
+		}
+	}
+	logging.Info("ComputeTrend: %d snapshots of %s", len(trend.Points), root)
+	return trend, nil
+}
+
+func computeSnapshot(root string, c history.Commit, dir string) (TrendPoint, error) {
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return TrendPoint{}, err
+	}
+	if err := history.Extract(root, c.SHA, dir); err != nil {
+		return TrendPoint{}, err
+	}
+	point := TrendPoint{Commit: c.SHA, Time: c.Time, Tag: c.Tag, Languages: make(map[string]LineTotals)}
+	ps := ProjectStats{Root: dir}
+	_, _, _, err := walkDir(dir, func(path string) error {
+		f, err := ps.computeFile(path)
+		if err != nil {
+			return err
+		}
+		point.Totals.add(f)
+		addLanguage(point.Languages, f)
+		return nil
+	})
+	return point, err
+}
+
+// LanguageNames возвращает языки, встречающиеся в снимках, по убыванию
+// строк кода в последнем снимке, а затем по имени.
+func (t Trend) LanguageNames() []string {
+	var last map[string]LineTotals
+	if len(t.Points) > 0 {
+		last = t.Points[len(t.Points)-1].Languages
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range t.Points {
+		for name := range p.Languages {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if last[names[i]].Code != last[names[j]].Code {
+			return last[names[i]].Code > last[names[j]].Code
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+var trendExporters = map[string]func(io.Writer, Trend) error{
+	FormatJSON: WriteTrendJSON,
+	FormatCSV:  WriteTrendCSV,
+}
+
+// TrendFormats возвращает отсортированный список форматов вывода тренда.
+func TrendFormats() []string {
+	names := make([]string, 0, len(trendExporters))
+	for name := range trendExporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteTrend пишет тренд в указанном формате (по умолчанию JSON).
+func WriteTrend(w io.Writer, t Trend, format string) error {
+	if format == "" {
+		format = FormatJSON
+	}
+	export, ok := trendExporters[format]
+	if !ok {
+		return fmt.Errorf("unknown trend format %q", format)
+	}
+	return export(w, t)
+}
+
+func WriteTrendJSON(w io.Writer, t Trend) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+var csvTrendHeader = []string{
+	"time", "commit", "tag", "language",
+	"files", "lines_total", "lines_code", "lines_comments", "lines_blank",
+}
+
+// WriteTrendCSV пишет тренд в «длинном» виде: по строке на снимок и
+// язык. Строка с пустым language — итоги снимка по всем файлам.
+func WriteTrendCSV(w io.Writer, t Trend) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvTrendHeader); err != nil {
+		return err
+	}
+	languages := t.LanguageNames()
+	for _, p := range t.Points {
+		row := func(language string, lt LineTotals) []string {
+			return []string{
+				p.Time.Format(time.RFC3339), p.Commit, p.Tag, language,
+				strconv.Itoa(lt.Files), strconv.Itoa(lt.Lines), strconv.Itoa(lt.Code),
+				strconv.Itoa(lt.Comments), strconv.Itoa(lt.Blank),
+			}
+		}
+		if err := cw.Write(row("", p.Totals)); err != nil {
+			return err
+		}
+		for _, name := range languages {
+			lt, ok := p.Languages[name]
+			if !ok {
+				continue
+			}
+			if err := cw.Write(row(name, lt)); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
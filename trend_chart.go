@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/rfxxfy/LintVision/stats"
+)
+
+// trendSeries — сколько языков с наибольшим числом строк кода
+// показывать на графике.
+const trendSeries = 6
+
+var trendPalette = []color.Color{
+	color.NRGBA{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+	color.NRGBA{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+	color.NRGBA{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+	color.NRGBA{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+	color.NRGBA{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+	color.NRGBA{R: 0x8c, G: 0x56, B: 0x4b, A: 0xff},
+}
+
+// trendChart — линейный график строк кода по языкам во времени.
+type trendChart struct {
+	widget.BaseWidget
+	trend stats.Trend
+}
+
+func newTrendChart() *trendChart {
+	c := &trendChart{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *trendChart) SetTrend(t stats.Trend) {
+	c.trend = t
+	c.Refresh()
+}
+
+func (c *trendChart) CreateRenderer() fyne.WidgetRenderer {
+	return &trendChartRenderer{chart: c}
+}
+
+type trendChartRenderer struct {
+	chart   *trendChart
+	size    fyne.Size
+	objects []fyne.CanvasObject
+}
+
+func (r *trendChartRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.build()
+}
+
+func (r *trendChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(320, 200)
+}
+
+func (r *trendChartRenderer) Refresh() {
+	r.build()
+	canvas.Refresh(r.chart)
+}
+
+func (r *trendChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *trendChartRenderer) Destroy() {}
+
+// build строит оси, линии по языкам и легенду под текущий размер.
+func (r *trendChartRenderer) build() {
+	r.objects = r.objects[:0]
+	points := r.chart.trend.Points
+	fg := theme.Color(theme.ColorNameForeground)
+	if len(points) == 0 {
+		text := canvas.NewText("Нет данных: постройте тренд по истории git", fg)
+		text.Move(fyne.NewPos(8, 8))
+		r.objects = append(r.objects, text)
+		return
+	}
+
+	languages := r.chart.trend.LanguageNames()
+	if len(languages) > trendSeries {
+		languages = languages[:trendSeries]
+	}
+	maxCode := 1
+	for _, p := range points {
+		for _, name := range languages {
+			maxCode = max(maxCode, p.Languages[name].Code)
+		}
+	}
+
+	const left, top, right, bottom, legendWidth = 60, 10, 10, 30, 160
+	plotW := r.size.Width - left - right - legendWidth
+	plotH := r.size.Height - top - bottom
+	if plotW <= 0 || plotH <= 0 {
+		return
+	}
+	x := func(i int) float32 {
+		if len(points) == 1 {
+			return left + plotW/2
+		}
+		return left + plotW*float32(i)/float32(len(points)-1)
+	}
+	y := func(v int) float32 {
+		return top + plotH - plotH*float32(v)/float32(maxCode)
+	}
+
+	axisX := canvas.NewLine(fg)
+	axisX.Position1, axisX.Position2 = fyne.NewPos(left, top+plotH), fyne.NewPos(left+plotW, top+plotH)
+	axisY := canvas.NewLine(fg)
+	axisY.Position1, axisY.Position2 = fyne.NewPos(left, top), fyne.NewPos(left, top+plotH)
+	r.objects = append(r.objects, axisX, axisY)
+	r.addText(fmt.Sprintf("%d", maxCode), fg, fyne.NewPos(4, top))
+	r.addText("0", fg, fyne.NewPos(4, top+plotH-14))
+	r.addText(pointLabel(points[0]), fg, fyne.NewPos(left, top+plotH+4))
+	if len(points) > 1 {
+		last := pointLabel(points[len(points)-1])
+		r.addText(last, fg, fyne.NewPos(left+plotW-float32(len(last))*7, top+plotH+4))
+	}
+
+	for s, name := range languages {
+		col := trendPalette[s%len(trendPalette)]
+		for i := 1; i < len(points); i++ {
+			line := canvas.NewLine(col)
+			line.StrokeWidth = 2
+			line.Position1 = fyne.NewPos(x(i-1), y(points[i-1].Languages[name].Code))
+			line.Position2 = fyne.NewPos(x(i), y(points[i].Languages[name].Code))
+			r.objects = append(r.objects, line)
+		}
+		if len(points) == 1 {
+			dot := canvas.NewCircle(col)
+			dot.Resize(fyne.NewSize(6, 6))
+			dot.Move(fyne.NewPos(x(0)-3, y(points[0].Languages[name].Code)-3))
+			r.objects = append(r.objects, dot)
+		}
+		legendY := top + float32(s)*20
+		mark := canvas.NewRectangle(col)
+		mark.Resize(fyne.NewSize(12, 12))
+		mark.Move(fyne.NewPos(r.size.Width-legendWidth+8, legendY+3))
+		r.objects = append(r.objects, mark)
+		r.addText(fmt.Sprintf("%s: %d", name, points[len(points)-1].Languages[name].Code), fg,
+			fyne.NewPos(r.size.Width-legendWidth+26, legendY))
+	}
+}
+
+func (r *trendChartRenderer) addText(s string, col color.Color, pos fyne.Position) {
+	text := canvas.NewText(s, col)
+	text.TextSize = 12
+	text.Move(pos)
+	r.objects = append(r.objects, text)
+}
+
+// pointLabel подписывает снимок тегом или датой коммита.
+func pointLabel(p stats.TrendPoint) string {
+	if p.Tag != "" {
+		return p.Tag
+	}
+	return p.Time.Format("2006-01-02")
+}
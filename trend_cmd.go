@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rfxxfy/LintVision/history"
+	"github.com/rfxxfy/LintVision/stats"
+)
+
+// runTrend выполняет `lintvision trend` и возвращает код выхода.
+func runTrend(args []string) int {
+	fs := flag.NewFlagSet("trend", flag.ContinueOnError)
+	dir := fs.String("path", ".", "git-репозиторий (или директория в нём) для анализа")
+	sample := fs.String("sample", history.SampleEvery, "какие коммиты брать: "+strings.Join(history.Samples(), ", "))
+	every := fs.Int("every", 10, "шаг для -sample "+history.SampleEvery+": каждый N-й коммит")
+	maxPoints := fs.Int("max", 0, "взять только N последних снимков (0 — все)")
+	format := fs.String("format", "", "формат вывода (по умолчанию определяется по расширению -out, иначе json): "+strings.Join(stats.TrendFormats(), ", "))
+	out := fs.String("out", "", "файл для сохранения результата (по умолчанию stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Использование: lintvision trend [флаги]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *format == "" {
+		*format = stats.FormatJSON
+		if strings.HasSuffix(strings.ToLower(*out), ".csv") {
+			*format = stats.FormatCSV
+		}
+	}
+
+	trend, err := stats.ComputeTrend(*dir, stats.TrendOptions{
+		Sample: *sample,
+		Every:  *every,
+		Max:    *maxPoints,
+		Progress: func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\rснимков: %d/%d", done, total)
+			if done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot compute trend: %v\n", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create %s: %v\n", *out, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := stats.WriteTrend(w, trend, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write trend: %v\n", err)
+		return 1
+	}
+	return 0
+}